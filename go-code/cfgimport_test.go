@@ -0,0 +1,330 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"bufio"
+	"cmd/compile/internal/types"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// This file imports plain-text SSA dumps (the per-phase block/value
+// listing GOSSAFUNC=name writes into ssa.html, not the HTML/JSON
+// wrapper around it) into []bloc, so a contributor can paste a compiler
+// dump into testdata/ instead of hand-transcribing a builder like
+// buildHeapSortCFG. Two deviations from the request's literal shape,
+// both forced by what's actually available in this snapshot:
+//
+//   - ParseSSADump takes a *Conf to resolve the type names a dump prints
+//     (<int>, <mem>, ...) against real *types.Type values; there is no
+//     way to produce a bloc's Valu calls without one.
+//   - LoadSSAHTML takes a testing.TB, because constructing a *Conf at
+//     all goes through testConfig, which only exists in this package's
+//     test binary. Production code in this module never builds a *Func
+//     from scratch (see taildup.go, regalloc.go, ...); only the test DSL
+//     does, so this importer lives alongside it as a _test.go file
+//     rather than as a separate production "cfgimport" package -- bloc
+//     and Conf are themselves only visible there.
+//
+// Because bloc/Valu resolve named operand references lazily inside
+// Conf.Fun (once every block is known), there's no separate phi
+// "rewiring" pass: operand names collected straight off the dump, in
+// whatever order they're printed, are enough.
+
+var (
+	reBlockHeader = regexp.MustCompile(`^(b\d+):\s*(?:<-\s*(.*))?$`)
+	reValueLine   = regexp.MustCompile(`^\s*(v\d+)(?:\s*\([^)]*\))?\s*=\s*(\w+)\s*<([^>]*)>\s*(?:\[([^\]]*)\])?\s*(.*)$`)
+	rePlain       = regexp.MustCompile(`^\s*Plain\s*->\s*(b\d+)\s*$`)
+	reIf          = regexp.MustCompile(`^\s*If\s+(v\d+)\s*->\s*(b\d+)\s+(b\d+)\s*$`)
+	reExit        = regexp.MustCompile(`^\s*(?:Exit|Ret)\s+(v\d+)\s*$`)
+)
+
+// dumpValue is one parsed `vN = Op <type> [auxint] args...` line.
+type dumpValue struct {
+	name, op, typ, aux string
+	args               []string
+}
+
+// ssaDumpTerm is the one non-value, non-header line a block ends with.
+type ssaDumpTerm struct {
+	kind       string // "plain", "if", "exit"
+	target     string // plain
+	cond, t, f string // if
+	ret        string // exit
+}
+
+// dumpBlock is one parsed `bN: <- preds` header plus its values and
+// terminator.
+type dumpBlock struct {
+	name  string
+	preds []string
+	vals  []dumpValue
+	term  *ssaDumpTerm
+}
+
+func (b *dumpBlock) terminator() (interface{}, error) {
+	if b.term == nil {
+		return nil, fmt.Errorf("no terminator found")
+	}
+	switch b.term.kind {
+	case "plain":
+		return Goto(b.term.target), nil
+	case "if":
+		return If(b.term.cond, b.term.t, b.term.f), nil
+	case "exit":
+		return Exit(b.term.ret), nil
+	}
+	return nil, fmt.Errorf("unknown terminator kind %q", b.term.kind)
+}
+
+// ssaDumpType maps the small, fixed vocabulary of type names this
+// module's own builders already print (see regalloc_bench_test.go) to
+// the *types.Type a bloc's Valu call needs. Anything else is reported as
+// an error rather than silently guessed at.
+func ssaDumpType(c *Conf, name string) (*types.Type, error) {
+	switch strings.TrimSpace(name) {
+	case "mem":
+		return types.TypeMem, nil
+	case "int", "int64":
+		return c.config.Types.Int64, nil
+	case "uintptr":
+		return c.config.Types.Uintptr, nil
+	case "bool":
+		return c.config.Types.Bool, nil
+	default:
+		return nil, fmt.Errorf("cfgimport: unrecognized type name %q", name)
+	}
+}
+
+// ssaDumpOpNames maps the printed Op name to the Op constant, for the
+// set of ops this module's builders use. Extend as new dumps need more.
+var ssaDumpOpNames = map[string]Op{
+	"InitMem":   OpInitMem,
+	"SP":        OpSP,
+	"SB":        OpSB,
+	"Arg":       OpArg,
+	"Const64":   OpConst64,
+	"ConstBool": OpConstBool,
+	"ConstNil":  OpConstNil,
+	"Add64":     OpAdd64,
+	"Sub64":     OpSub64,
+	"Less64":    OpLess64,
+	"Less64U":   OpLess64U,
+	"Leq64":     OpLeq64,
+	"Eq64":      OpEq64,
+	"Neq64":     OpNeq64,
+	"Not":       OpNot,
+	"Lsh64x64":  OpLsh64x64,
+	"Ctz64":     OpCtz64,
+	"Load":      OpLoad,
+	"Copy":      OpCopy,
+	"Phi":       OpPhi,
+}
+
+func buildSSADumpValu(c *Conf, v dumpValue) (interface{}, error) {
+	op, ok := ssaDumpOpNames[v.op]
+	if !ok {
+		return nil, fmt.Errorf("unrecognized op %q", v.op)
+	}
+	typ, err := ssaDumpType(c, v.typ)
+	if err != nil {
+		return nil, err
+	}
+	var auxInt int64
+	if v.aux != "" {
+		n, err := strconv.ParseInt(strings.TrimSpace(v.aux), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad auxint %q for %s: %w", v.aux, v.name, err)
+		}
+		auxInt = n
+	}
+	return Valu(v.name, op, typ, auxInt, nil, v.args...), nil
+}
+
+// ParseSSADump reads a plain-text SSA block/value dump from r and
+// returns it as []bloc, ready to pass to c.Fun. c supplies the
+// *types.Type values the dump's printed type names are resolved
+// against.
+func ParseSSADump(r io.Reader, c *Conf) ([]bloc, error) {
+	blocs, _, err := parseSSADumpBlocs(r, c)
+	return blocs, err
+}
+
+// parseSSADumpBlocs is ParseSSADump's implementation; it additionally
+// returns the first block's name (the dump's entry block), which
+// ParseSSADump's own signature has no room for but LoadSSAHTML needs to
+// call c.Fun.
+func parseSSADumpBlocs(r io.Reader, c *Conf) ([]bloc, string, error) {
+	blocks, order, err := scanSSADump(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	blocs := make([]bloc, 0, len(order))
+	for _, name := range order {
+		b := blocks[name]
+		entries := make([]interface{}, 0, len(b.vals)+1)
+		for _, v := range b.vals {
+			entry, err := buildSSADumpValu(c, v)
+			if err != nil {
+				return nil, "", fmt.Errorf("cfgimport: block %s: %w", name, err)
+			}
+			entries = append(entries, entry)
+		}
+		term, err := b.terminator()
+		if err != nil {
+			return nil, "", fmt.Errorf("cfgimport: block %s: %w", name, err)
+		}
+		entries = append(entries, term)
+		blocs = append(blocs, Bloc(name, entries...))
+	}
+	if len(order) == 0 {
+		return blocs, "", nil
+	}
+	return blocs, order[0], nil
+}
+
+// scanSSADump does the line-oriented parse: block headers, value lines,
+// and the one terminator line each block ends with, in the order blocks
+// first appear.
+func scanSSADump(r io.Reader) (map[string]*dumpBlock, []string, error) {
+	blocks := make(map[string]*dumpBlock)
+	var order []string
+	var cur *dumpBlock
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if m := reBlockHeader.FindStringSubmatch(line); m != nil {
+			name := m[1]
+			var preds []string
+			if m[2] != "" {
+				preds = strings.Fields(m[2])
+			}
+			b := &dumpBlock{name: name, preds: preds}
+			blocks[name] = b
+			order = append(order, name)
+			cur = b
+			continue
+		}
+		if cur == nil {
+			continue // preamble (func signature, comments) before the first block
+		}
+		if m := reValueLine.FindStringSubmatch(line); m != nil {
+			cur.vals = append(cur.vals, dumpValue{
+				name: m[1], op: m[2], typ: m[3], aux: m[4],
+				args: strings.Fields(m[5]),
+			})
+			continue
+		}
+		if m := rePlain.FindStringSubmatch(line); m != nil {
+			cur.term = &ssaDumpTerm{kind: "plain", target: m[1]}
+			continue
+		}
+		if m := reIf.FindStringSubmatch(line); m != nil {
+			cur.term = &ssaDumpTerm{kind: "if", cond: m[1], t: m[2], f: m[3]}
+			continue
+		}
+		if m := reExit.FindStringSubmatch(line); m != nil {
+			cur.term = &ssaDumpTerm{kind: "exit", ret: m[1]}
+			continue
+		}
+		return nil, nil, fmt.Errorf("cfgimport: unrecognized line %q", line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, err
+	}
+	return blocks, order, nil
+}
+
+// LoadSSAHTML reads the plain-text block dump at path (as GOSSAFUNC
+// writes it into ssa.html's first phase) and builds a *Func from it.
+func LoadSSAHTML(tb testing.TB, path string) (*Func, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	c := testConfig(tb)
+	blocs, entry, err := parseSSADumpBlocs(file, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocs) == 0 {
+		return nil, fmt.Errorf("cfgimport: %s: no blocks parsed", path)
+	}
+	fun := c.Fun(entry, blocs...)
+	return fun.f, nil
+}
+
+// TestParseSSADump parses a small hand-written dump in the format
+// GOSSAFUNC prints (one simple loop) and checks the resulting *Func has
+// the expected shape: three blocks, one back edge, and a conditional
+// (BlockIf) header with two predecessors.
+func TestParseSSADump(t *testing.T) {
+	const dump = `b1:
+	v1 = InitMem <mem>
+	v2 = Const64 <int> [0]
+	v3 = Const64 <int> [1]
+	v4 = Const64 <int> [10]
+	Plain -> b2
+b2: <- b1 b2
+	v5 = Phi <int> v2 v6
+	v6 = Add64 <int> v5 v3
+	v7 = Less64 <bool> v5 v4
+	If v7 -> b2 b3
+b3: <- b2
+	Exit v1
+`
+	c := testConfig(t)
+	blocs, entry, err := parseSSADumpBlocs(strings.NewReader(dump), c)
+	if err != nil {
+		t.Fatalf("parseSSADumpBlocs: %v", err)
+	}
+	if entry != "b1" {
+		t.Fatalf("entry = %q, want b1", entry)
+	}
+	if len(blocs) != 3 {
+		t.Fatalf("got %d blocs, want 3", len(blocs))
+	}
+
+	fun := c.Fun(entry, blocs...)
+	f := fun.f
+	if len(f.Blocks) != 3 {
+		t.Fatalf("got %d blocks, want 3", len(f.Blocks))
+	}
+
+	backEdges := 0
+	for _, b := range f.Blocks {
+		for _, e := range b.Succs {
+			if e.b == b {
+				backEdges++
+			}
+		}
+	}
+	if backEdges != 1 {
+		t.Errorf("got %d back edges, want 1", backEdges)
+	}
+	header, ok := fun.blocks["b2"]
+	if !ok {
+		t.Fatalf("block b2 missing from parsed Func")
+	}
+	if header.Kind != BlockIf {
+		t.Errorf("b2.Kind = %v, want BlockIf", header.Kind)
+	}
+	if len(header.Preds) != 2 {
+		t.Errorf("b2 has %d preds, want 2", len(header.Preds))
+	}
+}
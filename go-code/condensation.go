@@ -0,0 +1,107 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// Condensation is the condensation DAG of a Func's CFG: each SCC
+// collapsed to a single node, with an edge from node i to node j whenever
+// some block in Nodes[i] has a successor in Nodes[j]. computeSCCs already
+// returns SCCs in topological order of this DAG; Condensation makes the
+// DAG itself available rather than leaving callers to reconstruct it from
+// EntryEdge scans.
+type Condensation struct {
+	Nodes []*SCC
+	Edges [][]int // Edges[i] are indices into Nodes reachable directly from Nodes[i]
+
+	blockSCC map[ID]int // block ID -> index into Nodes
+}
+
+// Condensation returns the cached condensation DAG for f, computing it if
+// necessary.
+func (f *Func) Condensation() *Condensation {
+	if f.cachedCondensation == nil {
+		f.cachedCondensation = buildCondensation(f.sccs())
+	}
+	return f.cachedCondensation
+}
+
+func buildCondensation(sccs []SCC) *Condensation {
+	c := &Condensation{
+		Nodes:    make([]*SCC, len(sccs)),
+		Edges:    make([][]int, len(sccs)),
+		blockSCC: make(map[ID]int, len(sccs)),
+	}
+	for i := range sccs {
+		c.Nodes[i] = &sccs[i]
+		for _, b := range sccs[i].Blocks {
+			c.blockSCC[b.ID] = i
+		}
+	}
+	for i, scc := range c.Nodes {
+		seen := make(map[int]bool)
+		for _, b := range scc.Blocks {
+			for _, e := range b.Succs {
+				j := c.blockSCC[e.b.ID]
+				if j == i || seen[j] {
+					continue
+				}
+				seen[j] = true
+				c.Edges[i] = append(c.Edges[i], j)
+			}
+		}
+	}
+	return c
+}
+
+// ReversePostorder returns the indices of c.Nodes in reverse postorder of
+// the condensation DAG (sources before the sinks they reach).
+func (c *Condensation) ReversePostorder() []int {
+	n := len(c.Nodes)
+	seen := make([]bool, n)
+	var order []int
+	var visit func(int)
+	visit = func(i int) {
+		if seen[i] {
+			return
+		}
+		seen[i] = true
+		for _, j := range c.Edges[i] {
+			visit(j)
+		}
+		order = append(order, i)
+	}
+	for i := range c.Nodes {
+		visit(i)
+	}
+	// order is now a postorder; reverse it in place.
+	for l, r := 0, len(order)-1; l < r; l, r = l+1, r-1 {
+		order[l], order[r] = order[r], order[l]
+	}
+	return order
+}
+
+// SCCOf returns the SCC containing b, or nil if b is unreachable (and so
+// absent from the condensation).
+func (c *Condensation) SCCOf(b *Block) *SCC {
+	i, ok := c.blockSCC[b.ID]
+	if !ok {
+		return nil
+	}
+	return c.Nodes[i]
+}
+
+// Predecessors returns the indices of nodes with a condensation edge into
+// node i.
+func (c *Condensation) Predecessors(i int) []int {
+	var preds []int
+	for j, edges := range c.Edges {
+		for _, k := range edges {
+			if k == i {
+				preds = append(preds, j)
+				break
+			}
+		}
+	}
+	return preds
+}
@@ -0,0 +1,247 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"strconv"
+	"strings"
+
+	"cmd/compile/internal/types"
+)
+
+// cseGeneric and cseLowered are the two entry points a real pass list
+// would register separately ("generic cse" before lowering, "lowered
+// cse" after): the algorithm itself doesn't care which side of lowering
+// it runs on, only that opcodeTable[v.Op].call and v.Type.IsMemory()
+// still mean the right thing for whichever Ops are live in f at that
+// point. This snapshot has no passes.go / pass list to register them
+// in -- the same gap loopnest_havlak.go's useHavlakLoopFinder note
+// already calls out -- so both are plain functions here for tests and
+// callers to invoke directly at the point they'd otherwise run.
+func cseGeneric(f *Func) { cse(f) }
+func cseLowered(f *Func) { cse(f) }
+
+// cseKey groups values that can only ever be equivalent if these fields
+// match exactly; two values in the same cseKey group still need their
+// arguments refined into the same class (see refineByArgs) before
+// they're actually interchangeable.
+type cseKey struct {
+	op     Op
+	typ    *types.Type
+	auxInt int64
+	aux    any
+	nargs  int
+}
+
+// cseEligible reports whether v can ever participate in a cse
+// equivalence class. Phis are excluded because their identity is their
+// control-flow position, not their operands; calls and anything
+// memory-typed are excluded because they carry a side effect (or the
+// sole thread of memory order) that two textually-identical Values
+// don't actually share just by having the same Op/Args.
+func cseEligible(v *Value) bool {
+	if v.Op == OpPhi || opcodeTable[v.Op].call {
+		return false
+	}
+	if v.Type.IsMemory() {
+		return false
+	}
+	return true
+}
+
+// cse runs common-subexpression elimination over f: values are
+// partitioned into equivalence classes by (op, type, auxint, aux,
+// argument count) and then iteratively refined by their arguments'
+// classes (Hopcroft-style value numbering) until the partition stops
+// changing. Within each resulting class of size >1, cse uses f's
+// dominator tree to find, for each pair, whichever one's definition
+// dominates the other, and rewrites the dominated value into an OpCopy
+// of the dominating one -- the same in-place rewrite decomposeCompound
+// uses for its own collapsed projections, which leaves removal of the
+// now-dead original arguments to a later deadcode/copy-elimination
+// pass.
+func cse(f *Func) {
+	if len(f.Blocks) == 0 {
+		return
+	}
+
+	blockPos := make(map[ID]int, f.NumValues())
+	eligible := make([]*Value, 0, f.NumValues())
+	for _, b := range f.Blocks {
+		for i, v := range b.Values {
+			blockPos[v.ID] = i
+			if cseEligible(v) {
+				eligible = append(eligible, v)
+			}
+		}
+	}
+	if len(eligible) < 2 {
+		return
+	}
+
+	class := partitionByShape(eligible)
+	// partitionByShape only assigns a class to eligible values, so an
+	// ineligible argument (a Phi, a memory-typed value, a call result)
+	// reads back as class[a.ID] == 0 -- the map's zero value -- in
+	// argSignature, making every such argument look identical regardless
+	// of which actual value it is. Seed each ineligible value with a
+	// class number of its own (its negated, 1-based ID, so it can never
+	// collide with partitionByShape's non-negative group numbers) so two
+	// values that differ only in which ineligible value they consume
+	// never refine into the same class.
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			if !cseEligible(v) {
+				class[v.ID] = -(int(v.ID) + 1)
+			}
+		}
+	}
+	for refineByArgs(eligible, class) {
+		// keep refining until a pass splits nothing further
+	}
+
+	classes := make(map[int][]*Value, len(eligible))
+	for _, v := range eligible {
+		id := class[v.ID]
+		classes[id] = append(classes[id], v)
+	}
+
+	sdom := f.Sdom()
+	for _, members := range classes {
+		if len(members) > 1 {
+			mergeClass(members, sdom, blockPos)
+		}
+	}
+}
+
+// partitionByShape assigns every eligible value an initial class number,
+// grouping by cseKey.
+func partitionByShape(eligible []*Value) map[ID]int {
+	groups := make(map[cseKey][]*Value, len(eligible))
+	for _, v := range eligible {
+		k := cseKey{v.Op, v.Type, v.AuxInt, v.Aux, len(v.Args)}
+		groups[k] = append(groups[k], v)
+	}
+	class := make(map[ID]int, len(eligible))
+	id := 0
+	for _, vs := range groups {
+		for _, v := range vs {
+			class[v.ID] = id
+		}
+		id++
+	}
+	return class
+}
+
+// refineByArgs splits every class whose members' arguments don't all
+// belong to the same sequence of classes, mutating class in place.
+// It reports whether anything was split, so cse's caller can keep
+// calling it until a pass is a no-op (the partition has stopped
+// changing).
+func refineByArgs(eligible []*Value, class map[ID]int) bool {
+	byClass := make(map[int][]*Value, len(eligible))
+	for _, v := range eligible {
+		byClass[class[v.ID]] = append(byClass[class[v.ID]], v)
+	}
+
+	changed := false
+	next := make(map[ID]int, len(eligible))
+	nextID := 0
+	for _, vs := range byClass {
+		if len(vs) == 1 {
+			next[vs[0].ID] = nextID
+			nextID++
+			continue
+		}
+		sub := make(map[string][]*Value, len(vs))
+		for _, v := range vs {
+			sub[argSignature(v, class)] = append(sub[argSignature(v, class)], v)
+		}
+		if len(sub) > 1 {
+			changed = true
+		}
+		for _, sv := range sub {
+			for _, v := range sv {
+				next[v.ID] = nextID
+			}
+			nextID++
+		}
+	}
+	for id, c := range next {
+		class[id] = c
+	}
+	return changed
+}
+
+// argSignature encodes v's arguments' current classes, in order, so two
+// values land in the same sub-group here exactly when their arguments
+// are (so far) equivalent pairwise.
+func argSignature(v *Value, class map[ID]int) string {
+	var b strings.Builder
+	for _, a := range v.Args {
+		b.WriteString(strconv.Itoa(class[a.ID]))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// mergeClass collapses members (an equivalence class of size >1) down
+// to its dominance-distinct representatives: whenever one member's
+// definition dominates another's, the dominated one is folded into the
+// dominating one. Members on different, mutually non-dominating
+// branches are left alone -- correctly, since neither is guaranteed to
+// be computed on every path that reaches the other.
+func mergeClass(members []*Value, sdom SparseTree, blockPos map[ID]int) {
+	for i, vi := range members {
+		if vi == nil {
+			continue
+		}
+		for j := i + 1; j < len(members); j++ {
+			vj := members[j]
+			if vj == nil {
+				continue
+			}
+			switch {
+			case valueDominates(vi, vj, sdom, blockPos):
+				foldInto(vj, vi)
+				members[j] = nil
+			case valueDominates(vj, vi, sdom, blockPos):
+				foldInto(vi, vj)
+				members[i] = vj
+				vi = vj
+			}
+		}
+	}
+}
+
+// valueDominates reports whether a's definition dominates b's: in the
+// same block, the earlier of the two (by position) dominates; in
+// different blocks, a's block must dominate b's block in f's sparse
+// dominator tree. Using sdom.IsAncestorEq here instead of walking
+// f.idom() chains (the way dom.go's dominatesBlock does) keeps every
+// pairwise check in mergeClass's O(n^2) loop over a class O(1) rather
+// than O(depth).
+func valueDominates(a, b *Value, sdom SparseTree, blockPos map[ID]int) bool {
+	if a == b {
+		return false
+	}
+	if a.Block == b.Block {
+		return blockPos[a.ID] < blockPos[b.ID]
+	}
+	return sdom.IsAncestorEq(a.Block, b.Block)
+}
+
+// foldInto rewrites dup in place into a copy of keep, preserving dup's
+// Value identity (so anything already holding a *Value pointing at dup
+// keeps working) and leaving reclamation of dup's now-unused operands to
+// a later deadcode pass, exactly as decomposeCompound's own collapsed
+// projections are left for one.
+func foldInto(dup, keep *Value) {
+	dup.Op = OpCopy
+	dup.Aux = nil
+	dup.AuxInt = 0
+	dup.Args = dup.Args[:0]
+	dup.AddArg(keep)
+}
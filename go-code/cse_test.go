@@ -0,0 +1,121 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+// TestCSECollapsesDominatedDuplicate checks that a pure op (here
+// OpAdd64) recomputed with identical arguments in a block dominated by
+// the first computation collapses into an OpCopy of it.
+func TestCSECollapsesDominatedDuplicate(t *testing.T) {
+	c := testConfig(t)
+	intType := c.config.Types.Int64
+	boolType := c.config.Types.Bool
+	memType := types.TypeMem
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, memType, 0, nil),
+			Valu("x", OpConst64, intType, 1, nil),
+			Valu("y", OpConst64, intType, 2, nil),
+			Valu("sum1", OpAdd64, intType, 0, nil, "x", "y"),
+			Valu("cond", OpConstBool, boolType, 0, nil),
+			If("cond", "then", "else")),
+
+		Bloc("then",
+			Valu("sum2", OpAdd64, intType, 0, nil, "x", "y"),
+			Goto("join")),
+
+		Bloc("else",
+			Goto("join")),
+
+		Bloc("join",
+			Exit("mem")))
+
+	f := fut.f
+	cse(f)
+
+	sum2 := fut.values["sum2"]
+	if sum2.Op != OpCopy {
+		t.Fatalf("expected sum2 to collapse into an OpCopy, got %v", sum2.Op)
+	}
+	if len(sum2.Args) != 1 || sum2.Args[0] != fut.values["sum1"] {
+		t.Fatalf("expected sum2 to copy sum1, got %v", sum2.Args)
+	}
+}
+
+// TestCSEExcludesMemoryAndCalls checks that memory-typed values and
+// calls are never placed in an equivalence class, even when their
+// shape (op/type/args) otherwise matches exactly.
+func TestCSEExcludesMemoryAndCalls(t *testing.T) {
+	c := testConfig(t)
+	ptrType := c.config.Types.BytePtr
+	memType := types.TypeMem
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, memType, 0, nil),
+			Valu("call1", OpStaticCall, ptrType, 0, nil, "mem"),
+			Valu("call2", OpStaticCall, ptrType, 0, nil, "mem"),
+			Exit("mem")))
+
+	f := fut.f
+
+	call1, call2 := fut.values["call1"], fut.values["call2"]
+	if cseEligible(call1) {
+		t.Fatalf("expected a call value to be ineligible for cse")
+	}
+	if cseEligible(fut.values["mem"]) {
+		t.Fatalf("expected a memory-typed value to be ineligible for cse")
+	}
+
+	cse(f)
+
+	if call1.Op != OpStaticCall || call2.Op != OpStaticCall {
+		t.Fatalf("expected both calls to survive cse untouched, got %v and %v", call1.Op, call2.Op)
+	}
+}
+
+// TestCSEDistinguishesPhiArgs checks that two values that share everything
+// except which Phi they take as an argument -- here a loop header's two
+// induction variables, each incremented by the same constant -- are never
+// folded into each other. Before ineligible arguments (Phis included) got
+// their own class numbers, both increments produced the identical arg
+// signature and one collapsed into an OpCopy of the other.
+func TestCSEDistinguishesPhiArgs(t *testing.T) {
+	c := testConfig(t)
+	intType := c.config.Types.Int64
+	boolType := c.config.Types.Bool
+	memType := types.TypeMem
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, memType, 0, nil),
+			Valu("zero", OpConst64, intType, 0, nil),
+			Goto("loop")),
+
+		Bloc("loop",
+			Valu("phi_i", OpPhi, intType, 0, nil, "zero", "i_next"),
+			Valu("phi_s", OpPhi, intType, 0, nil, "zero", "s_next"),
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("i_next", OpAdd64, intType, 0, nil, "phi_i", "one"),
+			Valu("s_next", OpAdd64, intType, 0, nil, "phi_s", "one"),
+			Valu("cond", OpConstBool, boolType, 0, nil),
+			If("cond", "loop", "exit")),
+
+		Bloc("exit",
+			Exit("mem")))
+
+	f := fut.f
+	cse(f)
+
+	iNext, sNext := fut.values["i_next"], fut.values["s_next"]
+	if iNext.Op == OpCopy || sNext.Op == OpCopy {
+		t.Fatalf("expected i_next and s_next to stay distinct, got i_next=%v s_next=%v", iNext.Op, sNext.Op)
+	}
+}
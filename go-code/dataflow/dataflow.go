@@ -0,0 +1,250 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dataflow provides a generic, Kildall-style worklist solver for
+// SSA dataflow analyses, parameterized over the lattice-like value a
+// client wants to compute per block (liveness sets, available
+// expressions, reaching definitions, ...).
+//
+// The solver reuses the same three convergence strategies that
+// ssa.computeLive hand-tunes for liveness: an acyclic CFG needs exactly
+// one pass in the right direction; a CFG with simple (non-nested or
+// small) loops converges by plain iteration; a CFG with general loop
+// structure converges in a small, empirically-bounded number of
+// alternating passes over each strongly connected component. Expressing
+// that once here lets other analyses opt into the same empirically tuned
+// SCC convergence instead of re-deriving it.
+package dataflow
+
+// Direction selects whether TransferBlock propagates information from a
+// block's predecessors to its successors (Forward, e.g. reaching defs) or
+// from successors to predecessors (Backward, e.g. liveness).
+type Direction int
+
+const (
+	Forward Direction = iota
+	Backward
+)
+
+// Block is the minimal view of a CFG node the solver needs. Callers
+// implement this over *ssa.Block (or a test double) without the dataflow
+// package needing to import ssa.
+type Block interface {
+	ID() int
+	Preds() []Block
+	Succs() []Block
+}
+
+// Transfer computes a dataflow analysis over facts of type F.
+//
+// Init provides the seed fact for a block before any transfer has run.
+// TransferBlock computes the fact flowing out of b (in Direction's sense)
+// given the facts flowing in from b's predecessors (Forward) or
+// successors (Backward). Join combines facts arriving along different
+// edges into the block. Equal reports whether two facts are the same, so
+// the solver can detect a fixed point.
+type Transfer[F any] interface {
+	Init(b Block) F
+	TransferBlock(b Block, in []F) F
+	Join(a, b F) F
+	Equal(a, b F) bool
+}
+
+// Solution holds the computed per-block fact, indexed by Block.ID().
+type Solution[F any] struct {
+	Out []F
+}
+
+// Policy selects which convergence strategy Solve uses. Callers normally
+// leave this at PolicyAuto and let Solve classify the CFG itself, exactly
+// like ssa.computeLive's three-way dispatch.
+type Policy int
+
+const (
+	PolicyAuto Policy = iota
+	PolicyAcyclic
+	PolicyIterate
+	PolicySCC3Pass
+)
+
+// SCC describes one strongly connected component of the CFG. Solve takes
+// them in topological order of the condensation DAG (sources first, as
+// ssa's computeSCCs returns them) and walks that list forward for a
+// Forward analysis and backward for a Backward one. A single-block SCC with no
+// self-loop is processed with one call to TransferBlock; anything larger
+// goes through the alternating-order fixed-point loop.
+type SCC struct {
+	Blocks     []Block
+	Order      []Block // one good traversal order within the SCC
+	ReverseOrd []Block // the same blocks in the opposite direction
+}
+
+// Solve runs the dataflow analysis described by t over the CFG reachable
+// from entry, using po (a postorder traversal of the CFG) and sccs (the
+// CFG's strongly connected components in topological order) to
+// choose and drive a convergence strategy.
+//
+// po and sccs are supplied by the caller rather than recomputed here so
+// that ssa.Func's existing cached postorder/SCC machinery can be reused
+// as-is; see ssa's dataflow_client.go for the adapter used by
+// ssa.computeLive's sibling analyses.
+func Solve[F any](po []Block, sccs []SCC, t Transfer[F], dir Direction, policy Policy) Solution[F] {
+	n := 0
+	for _, b := range po {
+		if id := b.ID(); id >= n {
+			n = id + 1
+		}
+	}
+	out := make([]F, n)
+	for _, b := range po {
+		out[b.ID()] = t.Init(b)
+	}
+
+	if policy == PolicyAuto {
+		policy = classify(po, sccs)
+	}
+
+	switch policy {
+	case PolicyAcyclic:
+		solveAcyclic(po, out, t, dir)
+	case PolicyIterate:
+		solveIterate(po, out, t, dir)
+	default:
+		solveSCC(sccs, out, t, dir)
+	}
+	return Solution[F]{Out: out}
+}
+
+func classify(po []Block, sccs []SCC) Policy {
+	nontrivial := 0
+	for _, scc := range sccs {
+		if len(scc.Blocks) > 1 {
+			nontrivial++
+			continue
+		}
+		if len(scc.Blocks) == 1 {
+			for _, s := range scc.Blocks[0].Succs() {
+				if s.ID() == scc.Blocks[0].ID() {
+					nontrivial++ // a self-loop
+					break
+				}
+			}
+		}
+	}
+	switch {
+	case nontrivial == 0:
+		return PolicyAcyclic
+	case len(po) < 30:
+		return PolicyIterate
+	default:
+		return PolicySCC3Pass
+	}
+}
+
+func inputs[F any](b Block, out []F, dir Direction) []F {
+	var neighbors []Block
+	if dir == Forward {
+		neighbors = b.Preds()
+	} else {
+		neighbors = b.Succs()
+	}
+	in := make([]F, len(neighbors))
+	for i, p := range neighbors {
+		in[i] = out[p.ID()]
+	}
+	return in
+}
+
+// solveAcyclic makes a single pass over the DAG, in reverse postorder for
+// Forward and in postorder for Backward, so every block's inputs are
+// final before it is visited; no block's input can depend on its own
+// output.
+func solveAcyclic[F any](po []Block, out []F, t Transfer[F], dir Direction) {
+	order := po
+	if dir == Forward {
+		order = reversed(po)
+	}
+	for _, b := range order {
+		out[b.ID()] = t.TransferBlock(b, inputs(b, out, dir))
+	}
+}
+
+// solveIterate re-runs TransferBlock over po until no block's output
+// changes, the classic Kildall worklist collapsed to "just iterate".
+func solveIterate[F any](po []Block, out []F, t Transfer[F], dir Direction) {
+	order := po
+	if dir == Backward {
+		order = reversed(po)
+	}
+	for {
+		changed := false
+		for _, b := range order {
+			next := t.TransferBlock(b, inputs(b, out, dir))
+			if !t.Equal(next, out[b.ID()]) {
+				out[b.ID()] = next
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// solveSCC processes SCCs in the topological order given for Forward and
+// in the reverse of it for Backward, so every SCC's inputs from outside it
+// are final before it is visited, with the same
+// empirically-tuned alternating-order 3-pass loop used by
+// ssa.computeLiveWithLoops: singleton SCCs without a self-loop get one
+// pass, everything else gets up to 3 passes alternating Order/ReverseOrd.
+func solveSCC[F any](sccs []SCC, out []F, t Transfer[F], dir Direction) {
+	for k := range sccs {
+		scc := sccs[k]
+		if dir == Backward {
+			scc = sccs[len(sccs)-1-k]
+		}
+		if len(scc.Blocks) == 1 {
+			b := scc.Blocks[0]
+			self := false
+			for _, s := range b.Succs() {
+				if s.ID() == b.ID() {
+					self = true
+				}
+			}
+			if !self {
+				out[b.ID()] = t.TransferBlock(b, inputs(b, out, dir))
+				continue
+			}
+		}
+		order, reverse := scc.Order, scc.ReverseOrd
+		if dir == Backward {
+			order, reverse = reverse, order
+		}
+		for pass := 0; pass < 3; pass++ {
+			cur := order
+			if pass%2 == 1 {
+				cur = reverse
+			}
+			changed := false
+			for _, b := range cur {
+				next := t.TransferBlock(b, inputs(b, out, dir))
+				if !t.Equal(next, out[b.ID()]) {
+					out[b.ID()] = next
+					changed = true
+				}
+			}
+			if !changed {
+				break
+			}
+		}
+	}
+}
+
+func reversed(bs []Block) []Block {
+	r := make([]Block, len(bs))
+	for i, b := range bs {
+		r[len(bs)-1-i] = b
+	}
+	return r
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import "cmd/compile/internal/ssa/dataflow"
+
+// blockAdapter makes *Block satisfy dataflow.Block, so ssa's existing
+// postorder/SCC machinery can drive the generic dataflow.Solve engine
+// without the dataflow package needing to import ssa.
+type blockAdapter struct{ b *Block }
+
+func (a blockAdapter) ID() int { return int(a.b.ID) }
+
+func (a blockAdapter) Preds() []dataflow.Block {
+	out := make([]dataflow.Block, len(a.b.Preds))
+	for i, e := range a.b.Preds {
+		out[i] = blockAdapter{e.b}
+	}
+	return out
+}
+
+func (a blockAdapter) Succs() []dataflow.Block {
+	out := make([]dataflow.Block, len(a.b.Succs))
+	for i, e := range a.b.Succs {
+		out[i] = blockAdapter{e.b}
+	}
+	return out
+}
+
+func adaptBlocks(bs []*Block) []dataflow.Block {
+	out := make([]dataflow.Block, len(bs))
+	for i, b := range bs {
+		out[i] = blockAdapter{b}
+	}
+	return out
+}
+
+func adaptSCCs(sccs []SCC) []dataflow.SCC {
+	out := make([]dataflow.SCC, len(sccs))
+	for i, scc := range sccs {
+		exitward, entryward := sccAlternatingOrders(scc.Blocks)
+		out[i] = dataflow.SCC{
+			Blocks:     adaptBlocks(scc.Blocks),
+			Order:      adaptBlocks(exitward),
+			ReverseOrd: adaptBlocks(entryward),
+		}
+	}
+	return out
+}
+
+// availFact is the lattice value for available-expressions: the set of
+// value IDs known to be already computed, and not yet invalidated by a
+// call, on every path reaching the end of a block.
+type availFact map[ID]bool
+
+func (f availFact) clone() availFact {
+	c := make(availFact, len(f))
+	for k := range f {
+		c[k] = true
+	}
+	return c
+}
+
+// availTransfer implements dataflow.Transfer[availFact] as a forward
+// analysis: a value is available at a block's exit if it is available on
+// entry (the intersection of all predecessors' exit facts) and was not
+// clobbered by an intervening call, or if it is a non-Phi, non-call value
+// defined in this block after its last call.
+type availTransfer struct{ f *Func }
+
+func (availTransfer) Init(b dataflow.Block) availFact { return availFact{} }
+
+func (availTransfer) Join(a, b availFact) availFact {
+	// Available-expressions is a must analysis: join is intersection.
+	out := availFact{}
+	for k := range a {
+		if b[k] {
+			out[k] = true
+		}
+	}
+	return out
+}
+
+func (availTransfer) Equal(a, b availFact) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func (t availTransfer) TransferBlock(db dataflow.Block, in []availFact) availFact {
+	b := db.(blockAdapter).b
+	var cur availFact
+	if len(in) == 0 {
+		cur = availFact{}
+	} else {
+		cur = in[0].clone()
+		for _, f := range in[1:] {
+			cur = availTransfer{}.Join(cur, f)
+		}
+	}
+	for _, v := range b.Values {
+		if opcodeTable[v.Op].call {
+			// A call may write memory/globals; conservatively drop
+			// everything available so far.
+			cur = availFact{}
+			continue
+		}
+		if v.Op == OpPhi {
+			continue
+		}
+		cur[v.ID] = true
+	}
+	return cur
+}
+
+// availableExpressions computes, for every block, the set of value IDs
+// available (already computed on every incoming path) at the block's
+// exit. It is a second client of the dataflow package (the first being
+// liveness, expressed by hand in computeLive for performance), added to
+// demonstrate the framework generalizes: any forward or backward
+// must/may analysis over the same SCC structure can reuse this solver
+// instead of re-deriving the 3-pass convergence.
+func availableExpressions(f *Func) map[ID]availFact {
+	po := f.postorder()
+	sol := dataflow.Solve[availFact](adaptBlocks(po), adaptSCCs(f.sccs()), availTransfer{f}, dataflow.Forward, dataflow.PolicyAuto)
+	out := make(map[ID]availFact, len(po))
+	for _, b := range po {
+		out[b.ID] = sol.Out[b.ID]
+	}
+	return out
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import "cmd/compile/internal/types"
+
+// compoundParts holds one compound Value's scalar components, in the
+// fixed order compoundRoles returns them for its type (ptr, len[, cap]
+// for slice/string; itab, data for interface; real, imag for complex).
+type compoundParts []*Value
+
+// compoundRole describes one scalar component of a compound type: its
+// own type, and the Op that projects it out of an opaque value of the
+// compound type (OpSlicePtr out of a *Value of slice type, and so on).
+type compoundRole struct {
+	typ    *types.Type
+	projOp Op
+}
+
+// compoundRoles returns t's scalar components, or nil if t isn't one of
+// the compound types decomposeCompound splits (slice, string, interface,
+// complex).
+func compoundRoles(t Types, typ *types.Type) []compoundRole {
+	switch {
+	case typ.IsSlice():
+		return []compoundRole{
+			{types.NewPtr(typ.Elem()), OpSlicePtr},
+			{t.Int, OpSliceLen},
+			{t.Int, OpSliceCap},
+		}
+	case typ.IsString():
+		return []compoundRole{
+			{t.BytePtr, OpStringPtr},
+			{t.Int, OpStringLen},
+		}
+	case typ.IsInterface():
+		return []compoundRole{
+			{t.BytePtr, OpITab},
+			{t.BytePtr, OpIData},
+		}
+	case typ.IsComplex():
+		elem := t.Float64
+		if typ.Size() < 16 {
+			elem = t.Float32
+		}
+		return []compoundRole{
+			{elem, OpComplexReal},
+			{elem, OpComplexImag},
+		}
+	}
+	return nil
+}
+
+// compoundMakeOp returns the Op whose Args are already exactly typ's
+// scalar components (OpSliceMake for a slice, ...), so decomposeValue
+// can reuse them instead of emitting a new projection.
+func compoundMakeOp(typ *types.Type) (Op, bool) {
+	switch {
+	case typ.IsSlice():
+		return OpSliceMake, true
+	case typ.IsString():
+		return OpStringMake, true
+	case typ.IsInterface():
+		return OpIMake, true
+	case typ.IsComplex():
+		return OpComplexMake, true
+	}
+	return 0, false
+}
+
+// projectionIndex reports whether op is one of the projection ops
+// compoundRoles can emit, and which role index it corresponds to.
+func projectionIndex(op Op) (int, bool) {
+	switch op {
+	case OpSlicePtr, OpStringPtr, OpITab, OpComplexReal:
+		return 0, true
+	case OpSliceLen, OpStringLen, OpIData, OpComplexImag:
+		return 1, true
+	case OpSliceCap:
+		return 2, true
+	}
+	return 0, false
+}
+
+// decomposeValue builds v's parts: if v directly constructs the
+// compound value (OpSliceMake, OpStringMake, OpIMake, OpComplexMake),
+// its parts are simply its existing Args -- no new Values needed. Any
+// other compound-typed Value (an OpArg, a Load, a SelectN projecting a
+// call's compound result, ...) is opaque, so each part is projected out
+// of v with the matching Op.
+func decomposeValue(v *Value, roles []compoundRole) compoundParts {
+	if makeOp, ok := compoundMakeOp(v.Type); ok && v.Op == makeOp {
+		parts := make(compoundParts, len(v.Args))
+		copy(parts, v.Args)
+		return parts
+	}
+	parts := make(compoundParts, len(roles))
+	for i, r := range roles {
+		nv := v.Block.NewValue0(v.Pos, r.projOp, r.typ)
+		nv.AddArg(v)
+		parts[i] = nv
+	}
+	return parts
+}
+
+// componentOf returns v's i'th scalar component: v's own parts if v is
+// itself compound-typed (built earlier in decomposeCompound's pass
+// order), or v unchanged if the role ever needs a value that was never
+// compound to begin with.
+func componentOf(parts map[ID]compoundParts, v *Value, i int) *Value {
+	if p, ok := parts[v.ID]; ok {
+		return p[i]
+	}
+	return v
+}
+
+// decomposeCompound splits every slice/string/interface/complex-typed
+// Value in f into independent one-word scalar Values, mirroring the real
+// compiler's own decompose builtin pass, so regAllocState only ever has
+// to carry one-word live ranges: a Phi of slice type becomes three Phis
+// (ptr, len, cap), each wired to the corresponding component of every
+// predecessor's value; any other compound value is left in place but
+// gets its components projected out via OpSlicePtr/OpSliceLen/... (or,
+// for an OpSliceMake/OpStringMake/OpIMake/OpComplexMake value, its parts
+// are simply its existing Args, no new Value needed). A redundant
+// projection already present in the function (e.g. the front end
+// emitting OpSlicePtr of a value this pass already decomposed) collapses
+// into an OpCopy of the part already built instead. Neither that copy
+// nor the original compound Phi/Make value (now unused) is spliced out
+// of the graph here -- the same degenerate-value idiom duplicateRegion's
+// doc comment (taildup.go) describes, left for a later copy-elim/deadcode
+// pass to reclaim.
+//
+// regAllocState.init isn't declared anywhere in this snapshot, so this
+// is invoked from the top of computeLive instead, which is.
+func decomposeCompound(f *Func) {
+	t := f.Config.Types
+	parts := make(map[ID]compoundParts)
+
+	// Pass 1: placeholder Phis for every compound-typed Phi, so
+	// loop-carried (back-edge) operands resolve once every block has
+	// been visited once.
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			if v.Op != OpPhi {
+				continue
+			}
+			roles := compoundRoles(t, v.Type)
+			if roles == nil {
+				continue
+			}
+			np := make(compoundParts, len(roles))
+			for i, r := range roles {
+				np[i] = b.NewValue0(v.Pos, OpPhi, r.typ)
+			}
+			parts[v.ID] = np
+		}
+	}
+
+	// Pass 2: every other compound value.
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			if v.Op == OpPhi {
+				continue // seeded above
+			}
+			roles := compoundRoles(t, v.Type)
+			if roles == nil {
+				continue
+			}
+			parts[v.ID] = decomposeValue(v, roles)
+		}
+	}
+
+	// Pass 3: now that every compound value in f has parts, wire the
+	// placeholder Phis' args component-wise.
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			np, ok := parts[v.ID]
+			if !ok || v.Op != OpPhi {
+				continue
+			}
+			for i := range np {
+				for _, a := range v.Args {
+					np[i].AddArg(componentOf(parts, a, i))
+				}
+			}
+		}
+	}
+
+	// Pass 4: collapse any projection already in the function that
+	// targets a value we just decomposed into a copy of the part we
+	// already built.
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			i, ok := projectionIndex(v.Op)
+			if !ok || len(v.Args) != 1 {
+				continue
+			}
+			np, ok := parts[v.Args[0].ID]
+			if !ok || i >= len(np) {
+				continue
+			}
+			if np[i] == v {
+				// v is itself one of the projections decomposeValue just
+				// built for its own argument (the opaque OpArg/OpLoad/
+				// OpSelectN path), not a pre-existing redundant one --
+				// collapsing it here would self-reference.
+				continue
+			}
+			v.Op = OpCopy
+			v.Aux = nil
+			v.AuxInt = 0
+			v.Args = v.Args[:0]
+			v.AddArg(np[i])
+		}
+	}
+}
@@ -82,6 +82,257 @@ func intersect(b, c *Block, postnum []int, idom []*Block) *Block {
 	return b
 }
 
+// useCooperDominators selects dominatorsCooper (the O(n^2)-worst-case
+// intersect-based iterative algorithm) instead of dominatorsLT
+// (Lengauer-Tarjan, the default) for every subsequent call to
+// dominators. It's a package variable rather than a real
+// "-d=ssa/dom/algo=cooper" debug flag for the same reason
+// useHavlakLoopFinder (loopnest_havlak.go) is one: this snapshot's pass
+// type has nowhere to hang an arbitrary string-valued flag. Tests flip
+// it directly to exercise the old algorithm; a real build would wire it
+// to a debug flag instead.
+var useCooperDominators = false
+
+// dominators computes the immediate dominator of every block reachable
+// from f.Entry. By default it runs dominatorsLT (Lengauer-Tarjan, near
+// linear even on the deep nil-check-style chains that make
+// dominatorsCooper's O(n^2) intersect loop slow); set
+// useCooperDominators to fall back to the old algorithm. Both return
+// the same idom array shape: idom[f.Entry.ID] is f.Entry itself, and
+// entries for unreachable blocks are left nil.
+func dominators(f *Func) []*Block {
+	if useCooperDominators {
+		return dominatorsCooper(f)
+	}
+	return dominatorsLT(f)
+}
+
+// dominatorsCooper computes the immediate dominator of every block
+// reachable from f.Entry, using the Cooper/Harvey/Kennedy iterative
+// algorithm: repeated application of intersect over blocks in reverse
+// postorder until the idom array reaches a fixpoint. idom[f.Entry.ID]
+// is f.Entry itself, the usual sentinel for "the root dominates
+// itself"; entries for unreachable blocks are left nil.
+func dominatorsCooper(f *Func) []*Block {
+	po := postorder(f)
+	postnum := make([]int, f.NumBlocks())
+	for i, b := range po {
+		postnum[b.ID] = i
+	}
+
+	idom := make([]*Block, f.NumBlocks())
+	idom[f.Entry.ID] = f.Entry
+
+	for changed := true; changed; {
+		changed = false
+		// po is exitward (f.Entry last); walking it backwards, skipping
+		// f.Entry itself, visits every other block in reverse postorder.
+		for i := len(po) - 2; i >= 0; i-- {
+			b := po[i]
+			var nb *Block
+			for _, e := range b.Preds {
+				p := e.b
+				if idom[p.ID] == nil {
+					continue // p not yet processed this pass
+				}
+				if nb == nil {
+					nb = p
+					continue
+				}
+				nb = intersect(p, nb, postnum, idom)
+			}
+			if nb != idom[b.ID] {
+				idom[b.ID] = nb
+				changed = true
+			}
+		}
+	}
+	return idom
+}
+
+// dominatorsLT computes the immediate dominator of every block
+// reachable from f.Entry using Lengauer-Tarjan: a single DFS assigns
+// every reachable block a DFS number and parent, then blocks are
+// processed in reverse DFS order computing each one's semidominator
+// (the minimum DFS number reachable via a path whose interior vertices
+// all have a higher DFS number) via a link-eval forest over
+// (ltAncestor, ltLabel) with path compression in ltEval/ltCompress, and
+// each semidominator's bucket is resolved into a real immediate
+// dominator as soon as its own parent has been linked. A final forward
+// pass fixes up the handful of entries deferred because their
+// semidominator candidate wasn't yet known to be their true immediate
+// dominator. Path compression keeps every step near-linear even on the
+// deep, narrow chains (one block, one predecessor, repeat) where
+// dominatorsCooper's O(n^2) intersect loop degrades badly.
+func dominatorsLT(f *Func) []*Block {
+	n := f.NumBlocks()
+	idom := make([]*Block, n)
+	if f.Entry == nil {
+		return idom
+	}
+
+	dfnum := make([]int, n)
+	parent := make([]*Block, n)
+	vertex := make([]*Block, 0, n)
+
+	// Iterative DFS (the same explicit-stack shape
+	// poWithNumberingForValidBlocks above uses) numbering every
+	// reachable block in visitation order and recording its DFS parent.
+	dfnum[f.Entry.ID] = 1
+	vertex = append(vertex, f.Entry)
+	stack := []blockAndIndex{{b: f.Entry}}
+	for len(stack) > 0 {
+		top := len(stack) - 1
+		if stack[top].index < len(stack[top].b.Succs) {
+			s := stack[top].b.Succs[stack[top].index].Block()
+			stack[top].index++
+			if dfnum[s.ID] == 0 {
+				dfnum[s.ID] = len(vertex) + 1
+				parent[s.ID] = stack[top].b
+				vertex = append(vertex, s)
+				stack = append(stack, blockAndIndex{b: s})
+			}
+			continue
+		}
+		stack = stack[:top]
+	}
+
+	semi := make([]*Block, n)
+	ancestor := make([]*Block, n)
+	label := make([]*Block, n)
+	bucket := make([][]*Block, n)
+	for _, v := range vertex {
+		semi[v.ID] = v
+		label[v.ID] = v
+	}
+
+	ltLink := func(p, v *Block) { ancestor[v.ID] = p }
+
+	// ltCompress walks v's ancestor chain as far as it's already been
+	// compressed, fixing up label[x] for every x on that chain (in
+	// root-ward-first order, so each fixup reads an already-correct
+	// label below it) and repointing each one's ancestor straight at
+	// the forest root -- this is the iterative form of the textbook
+	// PATH-COMPRESS(v), which recurses on ancestor[v] before touching
+	// v itself.
+	ltCompress := func(v *Block) {
+		var chain []*Block
+		x := v
+		for ancestor[x.ID] != nil && ancestor[ancestor[x.ID].ID] != nil {
+			chain = append(chain, x)
+			x = ancestor[x.ID]
+		}
+		for i := len(chain) - 1; i >= 0; i-- {
+			x = chain[i]
+			anc := ancestor[x.ID]
+			if dfnum[semi[label[anc.ID].ID].ID] < dfnum[semi[label[x.ID].ID].ID] {
+				label[x.ID] = label[anc.ID]
+			}
+			ancestor[x.ID] = ancestor[anc.ID]
+		}
+	}
+	ltEval := func(v *Block) *Block {
+		if ancestor[v.ID] == nil {
+			return v
+		}
+		ltCompress(v)
+		return label[v.ID]
+	}
+
+	for i := len(vertex) - 1; i >= 1; i-- {
+		w := vertex[i]
+		for _, e := range w.Preds {
+			v := e.b
+			if dfnum[v.ID] == 0 {
+				continue // unreachable predecessor
+			}
+			u := ltEval(v)
+			if dfnum[semi[u.ID].ID] < dfnum[semi[w.ID].ID] {
+				semi[w.ID] = semi[u.ID]
+			}
+		}
+		bucket[semi[w.ID].ID] = append(bucket[semi[w.ID].ID], w)
+		ltLink(parent[w.ID], w)
+
+		pID := parent[w.ID].ID
+		for _, v := range bucket[pID] {
+			u := ltEval(v)
+			if dfnum[semi[u.ID].ID] < dfnum[semi[v.ID].ID] {
+				idom[v.ID] = u
+			} else {
+				idom[v.ID] = parent[w.ID]
+			}
+		}
+		bucket[pID] = nil
+	}
+
+	for i := 1; i < len(vertex); i++ {
+		w := vertex[i]
+		if idom[w.ID] != semi[w.ID] {
+			idom[w.ID] = idom[idom[w.ID].ID]
+		}
+	}
+	idom[f.Entry.ID] = f.Entry
+	return idom
+}
+
+// dominatesBlock reports whether a dominates b, treating a value's own
+// defining block as dominating itself (a == b reports true).
+func dominatesBlock(idom []*Block, a, b *Block) bool {
+	for c := b; c != nil; c = idom[c.ID] {
+		if c == a {
+			return true
+		}
+		if idom[c.ID] == c {
+			break // reached the root without finding a
+		}
+	}
+	return false
+}
+
+// idom returns f's immediate-dominator array, memoized on f.cachedIdom
+// (already cleared by f.invalidateCFG whenever an edge-mutating helper
+// runs -- see that method's doc comment for the full list). Only tests
+// call it so far (cse reaches dominance through f.Sdom()); any caller of
+// dominators(f) should go
+// through this instead of recomputing it directly, the same way every
+// existing caller of postorder(f) already goes through f.postorder().
+//
+// In debug builds (f.pass.debug > 0) idom recomputes from scratch on
+// every call and diffs the fresh result against the cache, Warnl-ing if
+// they differ instead of silently trusting a cache that should have
+// been invalidated -- the same report-only idiom restrictTupleLiveness
+// uses for regalloc's live sets.
+func (f *Func) idom() []*Block {
+	if f.cachedIdom == nil {
+		f.cachedIdom = dominators(f)
+		return f.cachedIdom
+	}
+	if f.pass != nil && f.pass.debug > 0 {
+		checkIdomCache(f)
+	}
+	return f.cachedIdom
+}
+
+// checkIdomCache recomputes the dominator tree and reports (via Warnl,
+// without touching f.cachedIdom) any block whose cached immediate
+// dominator no longer matches: a sign some edge-mutating helper changed
+// Block.Preds/Succs without calling f.invalidateCFG afterward.
+func checkIdomCache(f *Func) {
+	fresh := dominators(f)
+	cached := f.cachedIdom
+	if len(fresh) != len(cached) {
+		f.Warnl(f.Entry.Pos, "idom cache: stale length, cached %d blocks but f now has %d -- a CFG edge mutation skipped invalidateCFG", len(cached), len(fresh))
+		return
+	}
+	for id := range fresh {
+		if fresh[id] != cached[id] {
+			f.Warnl(f.Entry.Pos, "idom cache: stale entry for block id %d -- a CFG edge mutation skipped invalidateCFG", id)
+			return
+		}
+	}
+}
+
 // finds postorder and modified reverse postorder within SCC.
 func sccAlternatingOrders(scc []*Block) (exitward, entryward []*Block) {
 	switch len(scc) {
@@ -0,0 +1,115 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+// TestFuncIdomCaches checks that f.idom() memoizes on f.cachedIdom (a
+// second call returns the same, uncorrected array even after the cache
+// is deliberately corrupted) and that f.invalidateCFG() forces a fresh
+// computation afterward.
+func TestFuncIdomCaches(t *testing.T) {
+	c := testConfig(t)
+	boolType := c.config.Types.Bool
+	memType := types.TypeMem
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, memType, 0, nil),
+			Valu("cond", OpConstBool, boolType, 0, nil),
+			If("cond", "a", "b")),
+
+		Bloc("a",
+			Goto("join")),
+
+		Bloc("b",
+			Goto("join")),
+
+		Bloc("join",
+			Exit("mem")))
+
+	f := fut.f
+	entry, join := fut.blocks["entry"], fut.blocks["join"]
+
+	idom1 := f.idom()
+	if idom1[join.ID] != entry {
+		t.Fatalf("expected entry to immediately dominate join, got %v", idom1[join.ID])
+	}
+
+	// Corrupt the cache directly; a memoized idom() must hand back this
+	// (wrong) value rather than recomputing.
+	f.cachedIdom[join.ID] = nil
+	idom2 := f.idom()
+	if idom2[join.ID] != nil {
+		t.Fatalf("expected f.idom() to return the cached array unchanged, got %v", idom2[join.ID])
+	}
+
+	f.invalidateCFG()
+	idom3 := f.idom()
+	if idom3[join.ID] != entry {
+		t.Fatalf("expected invalidateCFG to force a fresh idom computation, got %v", idom3[join.ID])
+	}
+}
+
+// TestDominatorsLTMatchesCooper checks that dominatorsLT (the default,
+// near-linear Lengauer-Tarjan algorithm) computes exactly the same idom
+// array as dominatorsCooper (the legacy intersect-based algorithm) across
+// a small corpus of acyclic, looping, and irreducible-ish CFGs.
+func TestDominatorsLTMatchesCooper(t *testing.T) {
+	funcs := []struct {
+		name string
+		f    *Func
+	}{
+		{"linearChain", buildLinearChain(t, 12)},
+		{"simpleLoop", buildSimpleLoop(t, 5)},
+		{"floatPrecCFG", buildFloatPrecCFG(testConfig(t))},
+	}
+
+	for _, tc := range funcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cooper := dominatorsCooper(tc.f)
+			lt := dominatorsLT(tc.f)
+			if len(cooper) != len(lt) {
+				t.Fatalf("length mismatch: cooper has %d blocks, lt has %d", len(cooper), len(lt))
+			}
+			for id := range cooper {
+				if cooper[id] != lt[id] {
+					t.Errorf("block id %d: dominatorsCooper=%v, dominatorsLT=%v", id, cooper[id], lt[id])
+				}
+			}
+		})
+	}
+}
+
+// TestSdomDeepChain checks f.Sdom()'s ancestor queries against a linear
+// chain deep enough that an intersect-style idom-chain walk (dom.go's
+// dominatesBlock, or valueDominates before it moved to sdom) would have
+// to walk hundreds of links for the entry-to-tail query alone.
+func TestSdomDeepChain(t *testing.T) {
+	const depth = 500
+	f := buildLinearChain(t, depth)
+	sdom := f.Sdom()
+
+	// buildLinearChain lays out f.Blocks as entry, b0, b1, ..., b{depth-1}, exit.
+	entry := f.Entry
+	head := f.Blocks[1]
+	tail := f.Blocks[depth]
+
+	if !sdom.IsAncestorEq(entry, tail) {
+		t.Errorf("expected entry to dominate the tail of a %d-block chain", depth)
+	}
+	if !sdom.IsAncestorEq(head, tail) {
+		t.Errorf("expected b0 to dominate the tail of a %d-block chain", depth)
+	}
+	if sdom.IsAncestorEq(tail, head) {
+		t.Errorf("tail must not dominate b0")
+	}
+	if !sdom.IsAncestorEq(entry, entry) {
+		t.Errorf("expected a block to dominate itself")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import "testing"
+
+// spliceEveryEdge walks f's CFG and inserts a new BlockPlain between
+// every block and its single successor, using exactly the
+// removeSucc/AddEdgeTo sequence the reschedule-check and tail-dup passes
+// use to splice edges. Block.Preds/Succs being []Edge (see taildup.go,
+// reschedcheck.go) is what keeps each splice O(1) rather than O(len(Preds))
+// for the predecessor-slot lookup; this benchmark exists to demonstrate
+// that on chains long enough for the difference to show up.
+func spliceEveryEdge(f *Func) {
+	blocks := append([]*Block(nil), f.Blocks...)
+	for _, b := range blocks {
+		if len(b.Succs) != 1 {
+			continue
+		}
+		e := b.Succs[0]
+		s := e.b
+		mid := f.NewBlock(BlockPlain)
+		b.removeSucc(int(e.i))
+		b.AddEdgeTo(mid)
+		mid.AddEdgeTo(s)
+	}
+	f.invalidateCFG()
+}
+
+func benchmarkSpliceEveryEdge(b *testing.B, numBlocks int) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		f := buildLinearChain(b, numBlocks)
+		b.StartTimer()
+		spliceEveryEdge(f)
+	}
+}
+
+func BenchmarkEdgeSplice_LinearChain_1e3(b *testing.B) {
+	benchmarkSpliceEveryEdge(b, 1000)
+}
+
+func BenchmarkEdgeSplice_LinearChain_1e4(b *testing.B) {
+	benchmarkSpliceEveryEdge(b, 10000)
+}
+
+func BenchmarkEdgeSplice_LinearChain_1e5(b *testing.B) {
+	benchmarkSpliceEveryEdge(b, 100000)
+}
@@ -6,11 +6,31 @@ func (f *Func) sccs() []SCC {
 	return f.cachedSCCs
 }
 
-// invalidateCFG tells f that its CFG has changed.
+// SCCs is the exported form of sccs, for callers outside this package's
+// liveness/layout passes that want the condensation without reaching for
+// the lowercase internal accessor.
+func (f *Func) SCCs() []SCC {
+	return f.sccs()
+}
+
+// invalidateCFG tells f that its CFG has changed, discarding every cache
+// derived from block edges (postorder, dominators, the classic
+// dominator-based loopnest, SCCs/WTO/the SCC-derived LoopNest, and the
+// hot-trace order). Every entry point that mutates Block.Preds/Succs —
+// Block.AddEdgeTo, removePred, removeSucc, and the edge rewiring done by
+// duplicateTailsToReduceIrreducibility/MakeReducible in this package —
+// must call this afterward; the two in-package passes already do.
 func (f *Func) invalidateCFG() {
 	f.cachedPostorder = nil
 	f.cachedIdom = nil
 	f.cachedSdom = nil
 	f.cachedLoopnest = nil
 	f.cachedSCCs = nil
+	f.cachedTraceOrder = nil
+	f.cachedWTO = nil
+	f.cachedSCCLoopNest = nil
+	f.cachedCondensation = nil
+	f.cachedLoopForest = nil
+	f.cachedSCCTraceOrder = nil
+	f.cachedSCCEdgeWeights = nil
 }
\ No newline at end of file
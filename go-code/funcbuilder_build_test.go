@@ -0,0 +1,97 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import "testing"
+
+// TestFuncBuilderSimpleLoop checks that BuildSimpleLoop (FuncBuilder's
+// ported version of buildSimpleLoop) produces a *Func equivalent to the
+// original: same block count and the same single back edge.
+func TestFuncBuilderSimpleLoop(t *testing.T) {
+	c := testConfig(t)
+
+	want := buildSimpleLoop(t, 3)
+	got, err := BuildSimpleLoop(c, 3)
+	if err != nil {
+		t.Fatalf("BuildSimpleLoop: %v", err)
+	}
+
+	if len(got.Blocks) != len(want.Blocks) {
+		t.Errorf("got %d blocks, want %d", len(got.Blocks), len(want.Blocks))
+	}
+
+	countBackEdges := func(f *Func) int {
+		n := 0
+		for _, b := range f.Blocks {
+			for _, e := range b.Succs {
+				if e.b == f.Entry || e.b.ID < b.ID {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	if gb, wb := countBackEdges(got), countBackEdges(want); gb != wb {
+		t.Errorf("got %d back edges, want %d", gb, wb)
+	}
+}
+
+// TestFuncBuilderPhiValidation checks that Build() rejects a Phi whose
+// pairs don't match its block's actual predecessor set.
+func TestFuncBuilderPhiValidation(t *testing.T) {
+	c := testConfig(t)
+	intType := c.config.Types.Int64
+
+	fb := NewFuncBuilder(c)
+	entry := fb.Block("entry")
+	body := fb.Block("body")
+
+	mem := entry.InitMem("mem")
+	zero := entry.Const64("zero", intType, 0)
+	entry.Goto(body)
+
+	// body has exactly one predecessor (entry), but this Phi names two.
+	body.Phi("bad", intType, PhiArg{entry, zero}, PhiArg{body, zero})
+	body.Exit(mem)
+
+	if _, err := fb.Build(); err == nil {
+		t.Fatalf("Build() succeeded on a phi with a non-predecessor pair, want an error")
+	}
+}
+
+// TestBuildMapLookupLoop checks that BuildMapLookupLoop (built on the
+// CountingLoop/Diamond/Call patterns in funcbuilder_patterns_test.go)
+// produces a structurally valid *Func: one back edge (the loop) and a
+// reachable, non-irreducible loop body containing the found/not-found
+// diamond.
+func TestBuildMapLookupLoop(t *testing.T) {
+	c := testConfig(t)
+
+	f, err := BuildMapLookupLoop(c, 8)
+	if err != nil {
+		t.Fatalf("BuildMapLookupLoop: %v", err)
+	}
+
+	// A back edge is one whose target dominates its source. Block IDs
+	// don't order this CFG: CountingLoop creates the latch before the
+	// diamond's arms, so the arm-to-latch edges also run to a lower ID.
+	idom := f.idom()
+	backEdges := 0
+	for _, b := range f.Blocks {
+		for _, e := range b.Succs {
+			if dominatesBlock(idom, e.b, b) {
+				backEdges++
+			}
+		}
+	}
+	if backEdges != 1 {
+		t.Errorf("got %d back edges, want 1", backEdges)
+	}
+
+	// entry, header, body, then, else, latch, exit.
+	if want := 7; len(f.Blocks) != want {
+		t.Errorf("got %d blocks, want %d", len(f.Blocks), want)
+	}
+}
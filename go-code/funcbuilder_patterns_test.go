@@ -0,0 +1,166 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"fmt"
+)
+
+// This file adds a higher-level layer on top of FuncBuilder
+// (funcbuilder_test.go): instead of one BlockBuilder call per Value,
+// each method here emits the handful of blocks and values a whole
+// recurring shape needs. buildHeapSortCFG and buildFloatPrecCFG
+// themselves aren't rewritten onto this layer -- at ~400 lines of
+// already-working Valu/Bloc transcription with no new design content,
+// porting them is exactly the kind of busywork FuncBuilder's own doc
+// comment already declined for the same reason -- but BuildMapLookupLoop
+// below is a new realistic benchmark built entirely with it, exercising
+// both patterns this file adds.
+
+// LoopScaffold is the block/value handles a CountingLoop call hands
+// back. Body and Latch are separate blocks because the caller's own
+// control flow (e.g. a Diamond) may sit between them: Body is where the
+// header's If branches to on entering the loop, Latch is whatever block
+// ends up branching back to Header, and the two are only the same block
+// for a loop whose body never branches.
+type LoopScaffold struct {
+	Header *BlockBuilder
+	Body   *BlockBuilder
+	Latch  *BlockBuilder
+	Exit   *BlockBuilder
+	I      ValueHandle // the induction variable, as seen inside Body, Latch and Exit
+	prefix string
+}
+
+// CountingLoop emits a bounded for-loop's scaffolding: a header block
+// with the induction Phi and an i<limit test, an empty body block the
+// caller fills in, an empty latch block the caller's control flow must
+// eventually reach and call Close on, and an exit block. Latch is
+// created (and wired into the header's induction Phi) up front, before
+// the caller has decided what feeds into it, the same way BuildSimpleLoop
+// forward-references its own i_inc by name across the back edge --
+// here the forward reference is to a block identity rather than a value
+// name, since Phi's pairs need the actual BlockBuilder Latch will turn
+// out to be.
+func (fb *FuncBuilder) CountingLoop(prefix string, entry *BlockBuilder, intType, boolType *types.Type, init, limit ValueHandle) *LoopScaffold {
+	header := fb.Block(prefix + "_header")
+	body := fb.Block(prefix + "_body")
+	latch := fb.Block(prefix + "_latch")
+	exit := fb.Block(prefix + "_exit")
+	entry.Goto(header)
+
+	iInc := ValueHandle{name: prefix + "_i_inc"}
+	i := header.Phi(prefix+"_i", intType, PhiArg{entry, init}, PhiArg{latch, iInc})
+	cmp := header.Less64(prefix+"_cmp", boolType, i, limit)
+	header.If(cmp, body, exit)
+
+	return &LoopScaffold{Header: header, Body: body, Latch: latch, Exit: exit, I: i, prefix: prefix}
+}
+
+// Close finishes the loop: once the caller's control flow out of Body
+// has reached Latch (directly, or through however many blocks sit
+// between them), Close adds one to I under the "<prefix>_i_inc" name the
+// header's Phi already references, then branches Latch back to Header.
+func (ls *LoopScaffold) Close(intType *types.Type, one ValueHandle) {
+	ls.Latch.Add64(ls.prefix+"_i_inc", intType, ls.I, one)
+	ls.Latch.Goto(ls.Header)
+}
+
+// DiamondScaffold is the block handles a Diamond call hands back so its
+// caller can fill in the two arms and then Goto them both into Join.
+type DiamondScaffold struct {
+	Then, Else, Join *BlockBuilder
+}
+
+// DiamondTo emits a conditional branch's then/else scaffolding off of
+// pred, merging into the given, already-existing join block: pred
+// branches on cond to Then or Else, both of which the caller fills in
+// and then Gotos to join (this is the "growslice-style realloc branch" /
+// "memmove-or-not diamond" shape buildFloatPrecCFG already hand-writes
+// for q_ptr_new and mem63). join's merge Phi is just an ordinary
+// BlockBuilder.Phi call once both arms are closed -- DiamondTo only
+// wires the branch and the two arms' shared successor. Taking join as a
+// parameter (rather than always creating one) is what lets a Diamond
+// merge straight into a CountingLoop's Latch.
+func (fb *FuncBuilder) DiamondTo(prefix string, pred *BlockBuilder, cond ValueHandle, join *BlockBuilder) *DiamondScaffold {
+	then := fb.Block(prefix + "_then")
+	els := fb.Block(prefix + "_else")
+	pred.If(cond, then, els)
+	return &DiamondScaffold{Then: then, Else: els, Join: join}
+}
+
+// Diamond is DiamondTo with a fresh join block of its own, for a branch
+// that doesn't need to merge into some other scaffold's existing block.
+func (fb *FuncBuilder) Diamond(prefix string, pred *BlockBuilder, cond ValueHandle) *DiamondScaffold {
+	return fb.DiamondTo(prefix, pred, cond, fb.Block(prefix+"_join"))
+}
+
+// Call emits name_call as an OpStaticCall and projects resultTypes out
+// of it via OpSelectN, one per type, in order. This mirrors the ad hoc
+// OpStaticCall+OpSelectN convention buildFloatPrecCFG's own call sites
+// already use (e.g. div5_call's 4-tuple) rather than introducing a new
+// one; unifying that convention itself (and excluding unused results
+// from liveness) is out of scope here.
+func (b *BlockBuilder) Call(name string, memType *types.Type, resultTypes []*types.Type, args ...ValueHandle) []ValueHandle {
+	call := b.value(name+"_call", OpStaticCall, memType, 0, args...)
+	results := make([]ValueHandle, len(resultTypes))
+	for i, t := range resultTypes {
+		results[i] = b.value(fmt.Sprintf("%s_r%d", name, i), OpSelectN, t, int64(i), call)
+	}
+	return results
+}
+
+// BuildMapLookupLoop builds a loop over n keys, each iteration looking
+// up a key (lookup_call, a 2-tuple of (value, found) over mem) and
+// branching on found: the hit path keeps the looked-up value, the miss
+// path calls an insert routine (returning the default value) to get one
+// instead. Both paths merge back into the loop's latch with a Phi for
+// the value, and the per-iteration value accumulates into a running sum.
+// mem is carried by the header's own mem Phi, which the latch feeds with
+// a Copy of that Phi, so neither call's memory result reaches the next
+// iteration. This is the realistic benchmark the CountingLoop and
+// Diamond patterns above exist to make cheap to build.
+func BuildMapLookupLoop(c *Conf, n int) (*Func, error) {
+	intType := c.config.Types.Int64
+	boolType := c.config.Types.Bool
+	memType := types.TypeMem
+
+	fb := NewFuncBuilder(c)
+	entry := fb.Block("entry")
+
+	mem0 := entry.InitMem("mem0")
+	zero := entry.Const64("zero", intType, 0)
+	one := entry.Const64("one", intType, 1)
+	nKeys := entry.Const64("n_keys", intType, int64(n))
+
+	loop := fb.CountingLoop("loop", entry, intType, boolType, zero, nKeys)
+
+	sumIn := ValueHandle{name: "sum_inc"}
+	sum := loop.Header.Phi("sum", intType, PhiArg{entry, zero}, PhiArg{loop.Latch, sumIn})
+	memIn := ValueHandle{name: "mem_inc"}
+	mem := loop.Header.Phi("mem", memType, PhiArg{entry, mem0}, PhiArg{loop.Latch, memIn})
+
+	lookup := loop.Body.Call("lookup", memType, []*types.Type{intType, boolType}, mem, loop.I)
+	val, found := lookup[0], lookup[1]
+
+	diamond := fb.DiamondTo("found", loop.Body, found, loop.Latch)
+
+	hitVal := diamond.Then.Copy("hit_val", intType, val)
+	diamond.Then.Goto(diamond.Join)
+
+	insert := diamond.Else.Call("insert", memType, []*types.Type{intType}, mem, loop.I)
+	missVal := insert[0]
+	diamond.Else.Goto(diamond.Join)
+
+	mergedVal := loop.Latch.Phi("merged_val", intType, PhiArg{diamond.Then, hitVal}, PhiArg{diamond.Else, missVal})
+	loop.Latch.Add64("sum_inc", intType, sum, mergedVal)
+	loop.Latch.Copy("mem_inc", memType, mem)
+
+	loop.Close(intType, one)
+	loop.Exit.Exit(ValueHandle{name: "mem"})
+
+	return fb.Build()
+}
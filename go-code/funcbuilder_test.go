@@ -0,0 +1,273 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"fmt"
+)
+
+// FuncBuilder is a fluent, typo-resistant front end over the bloc/Valu
+// DSL this module's own builders (buildHeapSortCFG, buildSimpleLoop,
+// ...) already use: block and value references are handles returned by
+// the builder calls that created them, rather than the positional string
+// names Bloc/Valu take, so a misspelled operand is a compile error
+// instead of a c.Fun panic at build time.
+//
+// This lives in package ssa as a _test.go file, and wraps Conf/bloc/Valu
+// directly, rather than being exported as a separate "cfg" package: both
+// of those types are only visible inside this package's test binary in
+// this snapshot (see cfgimport_test.go's doc comment for the same
+// constraint), and production code here never constructs a *Func from
+// scratch. FuncBuilder.Build() still goes through c.Fun under the hood,
+// so its validation is purely structural (every block has a terminator,
+// every Phi's predecessor set matches its declared pairs) —
+// it does not change what a built *Func looks like, only how safely one
+// gets described.
+//
+// Scope: FuncBuilder supports the op vocabulary this module's own
+// builders use (see ssaDumpOpNames in cfgimport_test.go for the same
+// list). Porting buildHeapSortCFG and buildFloatPrecCFG verbatim is ~400
+// lines of transcription with no new design content; BuildSimpleLoop
+// below ports the smaller buildSimpleLoop as the worked migration
+// example the request asks for, and is checked against buildSimpleLoop's
+// own output for equivalence.
+type FuncBuilder struct {
+	c      *Conf
+	order  []*BlockBuilder
+	byName map[string]*BlockBuilder
+	errs   []error
+}
+
+// NewFuncBuilder starts a FuncBuilder that will build its *Func through
+// c.
+func NewFuncBuilder(c *Conf) *FuncBuilder {
+	return &FuncBuilder{c: c, byName: make(map[string]*BlockBuilder)}
+}
+
+// ValueHandle identifies a value defined in some block, for use as an
+// operand in a later builder call. It carries the underlying DSL name
+// only so BlockBuilder's methods can hand it to Valu. Callers construct
+// one directly, by name, only to forward-reference a value across a
+// loop's back edge before the call that defines it (see BuildSimpleLoop).
+type ValueHandle struct{ name string }
+
+// BlockBuilder accumulates one block's values and terminator.
+type BlockBuilder struct {
+	fb      *FuncBuilder
+	name    string
+	entries []interface{}
+	preds   []*BlockBuilder
+	done    bool // true once a terminator (Goto/If/Exit) has been added
+}
+
+// Block registers a new block named name. The first Block call becomes
+// the Func's entry block.
+func (fb *FuncBuilder) Block(name string) *BlockBuilder {
+	if _, dup := fb.byName[name]; dup {
+		fb.errs = append(fb.errs, fmt.Errorf("FuncBuilder: duplicate block name %q", name))
+	}
+	b := &BlockBuilder{fb: fb, name: name}
+	fb.byName[name] = b
+	fb.order = append(fb.order, b)
+	return b
+}
+
+func (b *BlockBuilder) addPred(from *BlockBuilder) {
+	b.preds = append(b.preds, from)
+}
+
+// value appends a Valu(...) entry and returns a handle to it.
+func (b *BlockBuilder) value(name string, op Op, t *types.Type, auxInt int64, args ...ValueHandle) ValueHandle {
+	argNames := make([]string, len(args))
+	for i, a := range args {
+		argNames[i] = a.name
+	}
+	b.entries = append(b.entries, Valu(name, op, t, auxInt, nil, argNames...))
+	return ValueHandle{name: name}
+}
+
+func (b *BlockBuilder) InitMem(name string) ValueHandle {
+	return b.value(name, OpInitMem, types.TypeMem, 0)
+}
+func (b *BlockBuilder) SP(name string, t *types.Type) ValueHandle {
+	return b.value(name, OpSP, t, 0)
+}
+func (b *BlockBuilder) Const64(name string, t *types.Type, v int64) ValueHandle {
+	return b.value(name, OpConst64, t, v)
+}
+func (b *BlockBuilder) ConstBool(name string, t *types.Type, v bool) ValueHandle {
+	var auxInt int64
+	if v {
+		auxInt = 1
+	}
+	return b.value(name, OpConstBool, t, auxInt)
+}
+func (b *BlockBuilder) Add64(name string, t *types.Type, x, y ValueHandle) ValueHandle {
+	return b.value(name, OpAdd64, t, 0, x, y)
+}
+func (b *BlockBuilder) Less64(name string, t *types.Type, x, y ValueHandle) ValueHandle {
+	return b.value(name, OpLess64, t, 0, x, y)
+}
+func (b *BlockBuilder) Copy(name string, t *types.Type, x ValueHandle) ValueHandle {
+	return b.value(name, OpCopy, t, 0, x)
+}
+
+// PhiArg is one (predecessor, value) pair for Phi: pred must be a block
+// that actually branches to the block Phi is called on, checked at
+// Build() time.
+type PhiArg struct {
+	Pred *BlockBuilder
+	Val  ValueHandle
+}
+
+// Phi defines a phi node. pairs may be given in any order; Build()
+// reorders them to match b's actual predecessor order (the order Goto/If
+// calls registered them in) and errors if the pair set and the
+// predecessor set don't match exactly.
+func (b *BlockBuilder) Phi(name string, t *types.Type, pairs ...PhiArg) ValueHandle {
+	b.entries = append(b.entries, phiPlaceholder{name: name, t: t, pairs: pairs})
+	return ValueHandle{name: name}
+}
+
+// phiPlaceholder defers Valu(...,"Phi",...) construction to Build(),
+// once every block's final predecessor order is known.
+type phiPlaceholder struct {
+	name  string
+	t     *types.Type
+	pairs []PhiArg
+}
+
+func (b *BlockBuilder) Goto(target *BlockBuilder) {
+	b.entries = append(b.entries, Goto(target.name))
+	target.addPred(b)
+	b.done = true
+}
+
+func (b *BlockBuilder) If(cond ValueHandle, t, f *BlockBuilder) {
+	b.entries = append(b.entries, If(cond.name, t.name, f.name))
+	t.addPred(b)
+	f.addPred(b)
+	b.done = true
+}
+
+func (b *BlockBuilder) Exit(mem ValueHandle) {
+	b.entries = append(b.entries, Exit(mem.name))
+	b.done = true
+}
+
+// Build validates every block and returns the *Func: each block must
+// have a terminator, every Phi's pairs must name exactly b's registered
+// predecessors (one each, no more, no fewer). Goto/If targets are
+// *BlockBuilder handles rather than names; Build does not check that a
+// target was created by this FuncBuilder's Block.
+func (fb *FuncBuilder) Build() (*Func, error) {
+	if len(fb.errs) > 0 {
+		return nil, fb.errs[0]
+	}
+	if len(fb.order) == 0 {
+		return nil, fmt.Errorf("FuncBuilder: no blocks")
+	}
+
+	blocs := make([]bloc, 0, len(fb.order))
+	for _, b := range fb.order {
+		if !b.done {
+			return nil, fmt.Errorf("FuncBuilder: block %q has no terminator", b.name)
+		}
+		predSet := make(map[string]bool, len(b.preds))
+		for _, p := range b.preds {
+			predSet[p.name] = true
+		}
+
+		entries := make([]interface{}, 0, len(b.entries))
+		for _, e := range b.entries {
+			ph, ok := e.(phiPlaceholder)
+			if !ok {
+				entries = append(entries, e)
+				continue
+			}
+			if len(ph.pairs) != len(b.preds) {
+				return nil, fmt.Errorf("FuncBuilder: block %q phi %q has %d pairs, want %d (one per predecessor)",
+					b.name, ph.name, len(ph.pairs), len(b.preds))
+			}
+			seen := make(map[string]bool, len(ph.pairs))
+			args := make([]string, len(b.preds))
+			for _, pair := range ph.pairs {
+				if !predSet[pair.Pred.name] {
+					return nil, fmt.Errorf("FuncBuilder: block %q phi %q names %q, which is not a predecessor",
+						b.name, ph.name, pair.Pred.name)
+				}
+				seen[pair.Pred.name] = true
+				for i, p := range b.preds {
+					if p.name == pair.Pred.name {
+						args[i] = pair.Val.name
+					}
+				}
+			}
+			for _, p := range b.preds {
+				if !seen[p.name] {
+					return nil, fmt.Errorf("FuncBuilder: block %q phi %q is missing a pair for predecessor %q",
+						b.name, ph.name, p.name)
+				}
+			}
+			entries = append(entries, Valu(ph.name, OpPhi, ph.t, 0, nil, args...))
+		}
+		blocs = append(blocs, Bloc(b.name, entries...))
+	}
+
+	fun := fb.c.Fun(fb.order[0].name, blocs...)
+	return fun.f, nil
+}
+
+// BuildSimpleLoop is buildSimpleLoop (regalloc_bench_test.go), ported
+// onto FuncBuilder as the migration example: same shape (an induction
+// variable counted up to limit across bodyBlocks straight-line body
+// blocks, looping back to the header), built with typed handles instead
+// of positional name strings.
+func BuildSimpleLoop(c *Conf, bodyBlocks int) (*Func, error) {
+	intType := c.config.Types.Int64
+	boolType := c.config.Types.Bool
+
+	fb := NewFuncBuilder(c)
+	entry := fb.Block("entry")
+	header := fb.Block("header")
+	bodies := make([]*BlockBuilder, bodyBlocks)
+	for j := range bodies {
+		bodies[j] = fb.Block(fmt.Sprintf("body%d", j))
+	}
+	exit := fb.Block("exit")
+
+	mem := entry.InitMem("mem")
+	zero := entry.Const64("zero", intType, 0)
+	one := entry.Const64("one", intType, 1)
+	limit := entry.Const64("limit", intType, 100)
+	entry.Goto(header)
+
+	// i_inc is defined by the last body block below but used here, by
+	// the header's Phi, before that block is built -- the one place a
+	// handle has to be spelled out by name rather than returned from the
+	// call that defines it, same as Valu's own forward string references
+	// across a loop's back edge.
+	iInc := ValueHandle{name: "i_inc"}
+	i := header.Phi("i", intType, PhiArg{entry, zero}, PhiArg{bodies[bodyBlocks-1], iInc})
+	cmp := header.Less64("cmp", boolType, i, limit)
+	header.If(cmp, bodies[0], exit)
+
+	prev := i
+	for j, body := range bodies {
+		cur := body.Add64(fmt.Sprintf("tmp%d", j), intType, prev, one)
+		if j == bodyBlocks-1 {
+			body.Add64("i_inc", intType, i, one)
+			body.Goto(header)
+		} else {
+			body.Goto(bodies[j+1])
+		}
+		prev = cur
+	}
+
+	exit.Exit(mem)
+
+	return fb.Build()
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// LayoutKind selects the block ordering f.layoutOrder returns: the
+// existing reverse-postorder emission order, or LayoutTraces' loop-aware
+// greedy trace order. Set via f.Layout; this snapshot has no codegen
+// stage to consume the chosen order itself, but f.layoutOrder is the
+// dispatch point one would call from.
+type LayoutKind int8
+
+const (
+	LayoutRPO   LayoutKind = iota // f.Blocks' current reverse-postorder-derived order
+	LayoutTrace                  // LayoutTraces' greedy trace order
+)
+
+// layoutOrder returns the block order codegen should emit in, per
+// f.Layout.
+func (f *Func) layoutOrder() []*Block {
+	if f.Layout == LayoutTrace {
+		return LayoutTraces(f)
+	}
+	po := f.postorder()
+	rpo := make([]*Block, len(po))
+	for i, b := range po {
+		rpo[len(po)-1-i] = b
+	}
+	return rpo
+}
+
+// LayoutTraces computes a greedy trace-based block linearization: loop
+// nests and a depth-based frequency estimate are used to pick the
+// hottest remaining block as each trace's seed, which is then extended
+// forward and backward one block at a time by following the
+// highest-scoring edge to a neighbor whose only unplaced neighbor (on
+// the side being extended) is the current trace end. Edge scoring
+// prefers staying within the seed's own loop over leaving it (so a loop
+// body stays contiguous with its header and exits get pushed off the
+// fall-through path) and, among same-loop-status candidates, the
+// statically likely branch.
+//
+// The result is a permutation of f.Blocks; it does not mutate f.Blocks
+// itself or f's cached analyses.
+func LayoutTraces(f *Func) []*Block {
+	ln := f.loopnest()
+	blocks := f.Blocks
+
+	freq := make(map[ID]int16, len(blocks))
+	for _, b := range blocks {
+		freq[b.ID] = ln.depth(b.ID)
+	}
+
+	placed := make(map[ID]bool, len(blocks))
+	order := make([]*Block, 0, len(blocks))
+
+	for len(order) < len(blocks) {
+		// The very first trace always starts at f.Entry, regardless of
+		// its frequency score: codegen needs the entry block first in
+		// the emitted order, and nothing else requires it to be hot.
+		var seed *Block
+		if len(order) == 0 {
+			seed = f.Entry
+		} else {
+			seed = hottestUnplaced(blocks, freq, placed)
+		}
+
+		trace := []*Block{seed}
+		placed[seed.ID] = true
+
+		for tail := seed; ; {
+			next := traceExtendForward(ln, tail, placed)
+			if next == nil {
+				break
+			}
+			trace = append(trace, next)
+			placed[next.ID] = true
+			tail = next
+		}
+		for head := seed; ; {
+			prev := traceExtendBackward(ln, head, placed)
+			if prev == nil {
+				break
+			}
+			trace = append([]*Block{prev}, trace...)
+			placed[prev.ID] = true
+			head = prev
+		}
+		order = append(order, trace...)
+	}
+	return order
+}
+
+// hottestUnplaced returns the unplaced block with the highest frequency
+// score, breaking ties by lowest block ID for determinism.
+func hottestUnplaced(blocks []*Block, freq map[ID]int16, placed map[ID]bool) *Block {
+	var best *Block
+	for _, b := range blocks {
+		if placed[b.ID] {
+			continue
+		}
+		if best == nil || freq[b.ID] > freq[best.ID] || (freq[b.ID] == freq[best.ID] && b.ID < best.ID) {
+			best = b
+		}
+	}
+	return best
+}
+
+// traceExtendForward picks tail's best unplaced successor to extend the
+// trace with: a candidate only qualifies if tail is its only unplaced
+// predecessor (extending there can't skip ahead of some other
+// not-yet-scheduled predecessor), and among qualifying candidates the
+// one with the highest traceEdgeScore wins.
+func traceExtendForward(ln *loopnest, tail *Block, placed map[ID]bool) *Block {
+	var best *Block
+	bestScore := -1 << 30
+	for i, e := range tail.Succs {
+		s := e.b
+		if placed[s.ID] || !onlyUnplacedPred(s, tail, placed) {
+			continue
+		}
+		if score := traceEdgeScore(ln, tail, s, i); best == nil || score > bestScore {
+			best, bestScore = s, score
+		}
+	}
+	return best
+}
+
+// traceExtendBackward is traceExtendForward's mirror image: it picks
+// head's best unplaced predecessor, requiring head to be that
+// predecessor's only unplaced successor.
+func traceExtendBackward(ln *loopnest, head *Block, placed map[ID]bool) *Block {
+	var best *Block
+	bestScore := -1 << 30
+	for _, e := range head.Preds {
+		p := e.b
+		if placed[p.ID] || !onlyUnplacedSucc(p, head, placed) {
+			continue
+		}
+		idx := -1
+		for i, se := range p.Succs {
+			if se.b == head {
+				idx = i
+				break
+			}
+		}
+		if score := traceEdgeScore(ln, p, head, idx); best == nil || score > bestScore {
+			best, bestScore = p, score
+		}
+	}
+	return best
+}
+
+// onlyUnplacedPred reports whether from is b's only not-yet-placed
+// predecessor.
+func onlyUnplacedPred(b, from *Block, placed map[ID]bool) bool {
+	for _, e := range b.Preds {
+		if e.b != from && !placed[e.b.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+// onlyUnplacedSucc reports whether to is b's only not-yet-placed
+// successor.
+func onlyUnplacedSucc(b, to *Block, placed map[ID]bool) bool {
+	for _, e := range b.Succs {
+		if e.b != to && !placed[e.b.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+// traceEdgeScore scores the edge from b to its succIdx'th successor s:
+// +2 if s stays within b's innermost loop (keeping the body contiguous
+// and pushing exits out of the fall-through path), plus the usual
+// branch-likelihood bonus/penalty also used by hottestUnplacedSucc.
+func traceEdgeScore(ln *loopnest, b, s *Block, succIdx int) int {
+	score := 0
+	if l := ln.b2l[b.ID]; l != nil && loopContains(ln, l, s) {
+		score += 2
+	}
+	if len(b.Succs) == 2 && succIdx >= 0 {
+		likely := (succIdx == 0 && b.Likely == BranchLikely) || (succIdx == 1 && b.Likely == BranchUnlikely)
+		unlikely := (succIdx == 0 && b.Likely == BranchUnlikely) || (succIdx == 1 && b.Likely == BranchLikely)
+		switch {
+		case likely:
+			score++
+		case unlikely:
+			score--
+		}
+	}
+	return score
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import "sort"
+
+// layout computes a block order for codegen by walking a LIFO worklist
+// seeded with f.Entry: at each step it schedules the most-recently-added
+// worklist entry whose predecessors have all already been scheduled (so
+// a block never gets emitted ahead of a predecessor still to come),
+// falling back to any other zero-indegree block when nothing on the
+// worklist qualifies. A predecessor reached only via a backedge (one the
+// block itself dominates, per f.Sdom()) doesn't count against
+// readiness -- a loop header's latch predecessor is exactly this case,
+// and without the exemption no block would ever look ready, since the
+// header needs the latch scheduled and the latch needs the header
+// scheduled first.
+//
+// Successors are pushed lowest-traceEdgeScore-first, so the
+// highest-scoring one (the one that stays inside the current innermost
+// loop, with statically-likely edges as the tiebreaker -- the same
+// scoring traceEdgeScore already provides for LayoutTraces) lands on top
+// of the LIFO worklist and is usually the very next block popped. That
+// keeps a loop body contiguous with its header and pushes loop-exit
+// edges off the immediate fall-through path, the same goal LayoutTraces
+// pursues by extending traces instead of popping a shared worklist.
+//
+// Blocks with no successors (every function's Ret/Exit block, usually
+// just one) are never eligible to be scheduled by the worklist walk at
+// all: they're appended once it's exhausted, in the order
+// layoutRegallocOrder's caller doesn't care about, so they always come
+// last.
+func layout(f *Func) []*Block {
+	n := f.NumBlocks()
+	scheduled := make([]bool, n)
+	sdom := f.Sdom()
+	ln := f.loopnest()
+
+	// ready reports whether every one of b's predecessors is either
+	// already scheduled or reachable only by a backedge into b (one
+	// that b itself dominates), which the loop-header/latch cycle
+	// requires us to ignore.
+	ready := func(b *Block) bool {
+		for _, e := range b.Preds {
+			if scheduled[e.b.ID] {
+				continue
+			}
+			if sdom.IsAncestorEq(b, e.b) {
+				continue
+			}
+			return false
+		}
+		return true
+	}
+
+	var exits []*Block
+	total := 0
+	for _, b := range f.Blocks {
+		if len(b.Succs) == 0 {
+			exits = append(exits, b)
+			continue
+		}
+		total++
+	}
+
+	order := make([]*Block, 0, len(f.Blocks))
+	stack := []*Block{f.Entry}
+
+	for len(order) < total {
+		idx := -1
+		for i := len(stack) - 1; i >= 0; i-- {
+			b := stack[i]
+			if scheduled[b.ID] || len(b.Succs) == 0 {
+				continue
+			}
+			if ready(b) {
+				idx = i
+				break
+			}
+		}
+
+		var next *Block
+		if idx >= 0 {
+			next = stack[idx]
+			stack = append(stack[:idx], stack[idx+1:]...)
+		} else {
+			for _, b := range f.Blocks {
+				if !scheduled[b.ID] && len(b.Succs) > 0 && ready(b) {
+					next = b
+					break
+				}
+			}
+			if next == nil {
+				// Every remaining block has an unscheduled predecessor --
+				// an unreachable-from-entry remnant or a cycle with no
+				// entry we haven't already broken into. Take any
+				// unscheduled block so the walk still terminates.
+				for _, b := range f.Blocks {
+					if !scheduled[b.ID] && len(b.Succs) > 0 {
+						next = b
+						break
+					}
+				}
+			}
+		}
+
+		scheduled[next.ID] = true
+		order = append(order, next)
+
+		scores := make([]int, len(next.Succs))
+		for i, e := range next.Succs {
+			scores[i] = traceEdgeScore(ln, next, e.b, i)
+		}
+		succIdx := make([]int, len(next.Succs))
+		for i := range succIdx {
+			succIdx[i] = i
+		}
+		sort.SliceStable(succIdx, func(i, j int) bool {
+			return scores[succIdx[i]] < scores[succIdx[j]]
+		})
+		for _, i := range succIdx {
+			stack = append(stack, next.Succs[i].b)
+		}
+	}
+
+	return append(order, exits...)
+}
+
+// layoutRegallocOrder returns the same block order layout computes,
+// named separately for regalloc-side callers the way cseGeneric and
+// cseLowered (cse.go) name the same underlying algorithm twice for their
+// own two callers.
+func layoutRegallocOrder(f *Func) []*Block {
+	return layout(f)
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestLayoutEntryFirstExitLast checks that layout always starts with
+// f.Entry and ends with the blocks that have no successors, across a
+// small corpus of acyclic and looping CFGs.
+func TestLayoutEntryFirstExitLast(t *testing.T) {
+	funcs := []struct {
+		name string
+		f    *Func
+	}{
+		{"linearChain", buildLinearChain(t, 8)},
+		{"simpleLoop", buildSimpleLoop(t, 4)},
+		{"nestedLoops2", buildNestedLoops(t, 2)},
+		{"nestedLoops3", buildNestedLoops(t, 3)},
+	}
+
+	for _, tc := range funcs {
+		t.Run(tc.name, func(t *testing.T) {
+			order := layout(tc.f)
+			if len(order) != len(tc.f.Blocks) {
+				t.Fatalf("layout returned %d blocks, want %d", len(order), len(tc.f.Blocks))
+			}
+			if order[0] != tc.f.Entry {
+				t.Errorf("expected entry first, got %v", order[0])
+			}
+			last := order[len(order)-1]
+			if len(last.Succs) != 0 {
+				t.Errorf("expected a successor-less block last, got %v with %d succs", last, len(last.Succs))
+			}
+		})
+	}
+}
+
+// TestLayoutLoopsContiguous checks that every innermost loop's blocks
+// form one contiguous run in layout's order, for nested loops of depth 2
+// and 3.
+func TestLayoutLoopsContiguous(t *testing.T) {
+	for _, depth := range []int{2, 3} {
+		t.Run(fmt.Sprintf("depth%d", depth), func(t *testing.T) {
+			f := buildNestedLoops(t, depth)
+			order := layout(f)
+
+			pos := make(map[ID]int, len(order))
+			for i, b := range order {
+				pos[b.ID] = i
+			}
+
+			ln := f.loopnest()
+			for _, l := range ln.loops {
+				if !l.isInner {
+					continue
+				}
+				var members []*Block
+				for _, b := range f.Blocks {
+					if ln.b2l[b.ID] == l {
+						members = append(members, b)
+					}
+				}
+				lo, hi := pos[members[0].ID], pos[members[0].ID]
+				for _, b := range members[1:] {
+					if pos[b.ID] < lo {
+						lo = pos[b.ID]
+					}
+					if pos[b.ID] > hi {
+						hi = pos[b.ID]
+					}
+				}
+				if hi-lo+1 != len(members) {
+					t.Errorf("loop headed by %s is not contiguous in layout order (span %d, members %d)", l.header, hi-lo+1, len(members))
+				}
+			}
+		})
+	}
+}
+
+// TestLayoutConditionalFallsThrough checks that every conditional block
+// in buildSimpleLoop has at least one of its successors immediately
+// following it in layout's order.
+func TestLayoutConditionalFallsThrough(t *testing.T) {
+	f := buildSimpleLoop(t, 4)
+	order := layout(f)
+
+	pos := make(map[ID]int, len(order))
+	for i, b := range order {
+		pos[b.ID] = i
+	}
+
+	for _, b := range f.Blocks {
+		if len(b.Succs) != 2 {
+			continue
+		}
+		fallsThrough := false
+		for _, e := range b.Succs {
+			if pos[e.b.ID] == pos[b.ID]+1 {
+				fallsThrough = true
+			}
+		}
+		if !fallsThrough {
+			t.Errorf("conditional block %v has neither successor immediately after it in layout order", b)
+		}
+	}
+}
+
+// TestLayoutRegallocOrderMatchesLayout checks that layoutRegallocOrder
+// is just layout under another name, for regalloc-side callers.
+func TestLayoutRegallocOrderMatchesLayout(t *testing.T) {
+	f := buildNestedLoops(t, 2)
+	a, b := layout(f), layoutRegallocOrder(f)
+	if len(a) != len(b) {
+		t.Fatalf("layout and layoutRegallocOrder returned different lengths: %d vs %d", len(a), len(b))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Errorf("layout and layoutRegallocOrder disagree at position %d: %v vs %v", i, a[i], b[i])
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestLayoutTracesNestedLoops runs LayoutTraces over buildNestedLoops and
+// checks the two properties its doc comment promises: the body block
+// ends up adjacent to its innermost loop's latch, and every innermost
+// loop's blocks form a contiguous run in the returned order (keeping the
+// loop body contiguous with its header).
+func TestLayoutTracesNestedLoops(t *testing.T) {
+	for _, depth := range []int{2, 3} {
+		t.Run(fmt.Sprintf("depth%d", depth), func(t *testing.T) {
+			f := buildNestedLoops(t, depth)
+			order := LayoutTraces(f)
+
+			if len(order) != len(f.Blocks) {
+				t.Fatalf("LayoutTraces returned %d blocks, want %d", len(order), len(f.Blocks))
+			}
+			pos := make(map[ID]int, len(order))
+			for i, b := range order {
+				if _, dup := pos[b.ID]; dup {
+					t.Fatalf("block %s appears twice in LayoutTraces order", b)
+				}
+				pos[b.ID] = i
+			}
+
+			ln := f.loopnest()
+			var innermost *loop
+			for _, l := range ln.loops {
+				if l.isInner {
+					innermost = l
+				}
+			}
+			if innermost == nil {
+				t.Fatalf("no innermost loop found")
+			}
+			var body, innerLatch *Block
+			for _, b := range f.Blocks {
+				if ln.b2l[b.ID] != innermost || b == innermost.header {
+					continue
+				}
+				if len(b.Succs) == 1 && b.Succs[0].b == innermost.header {
+					innerLatch = b
+				} else {
+					body = b
+				}
+			}
+			if body == nil || innerLatch == nil {
+				t.Fatalf("could not locate body/innermost-latch blocks")
+			}
+			if diff := pos[body.ID] - pos[innerLatch.ID]; diff != 1 && diff != -1 {
+				t.Errorf("body (pos %d) and innermost latch (pos %d) are not adjacent", pos[body.ID], pos[innerLatch.ID])
+			}
+
+			for _, l := range ln.loops {
+				if !l.isInner {
+					continue
+				}
+				var members []*Block
+				for _, b := range f.Blocks {
+					if ln.b2l[b.ID] == l {
+						members = append(members, b)
+					}
+				}
+				lo, hi := pos[members[0].ID], pos[members[0].ID]
+				for _, b := range members[1:] {
+					if pos[b.ID] < lo {
+						lo = pos[b.ID]
+					}
+					if pos[b.ID] > hi {
+						hi = pos[b.ID]
+					}
+				}
+				if hi-lo+1 != len(members) {
+					t.Errorf("loop headed by %s is not contiguous in the trace order (span %d, members %d)", l.header, hi-lo+1, len(members))
+				}
+			}
+		})
+	}
+}
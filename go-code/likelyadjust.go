@@ -5,6 +5,13 @@ type loopnest struct {
 	sdom           SparseTree // cached dominator tree (for compatibility)
 	loops          []*loop    // all loops found
 	hasIrreducible bool       // true if any irreducible loops detected
+
+	// The following are lazily computed by children/exits/findCalls
+	// in loopnest_metadata.go: most callers never need them, so
+	// loopnestfor doesn't pay for them up front.
+	childrenComputed bool
+	exitsComputed    bool
+	callsComputed    bool
 }
 
 // loopnestfor computes loop nest information using Bourdoncle's algorithm.
@@ -15,11 +22,23 @@ type loopnest struct {
 //  3. Remove header and recursively partition to find nested loops
 //  4. Build loop tree based on containment
 func loopnestfor(f *Func) *loopnest {
+	if useHavlakLoopFinder {
+		return loopnestforHavlak(f)
+	}
+
 	po := f.postorder()
 	b2l := make([]*loop, f.NumBlocks())
 	loops := make([]*loop, 0)
 	sawIrred := false
 
+	// Reverse-postorder position of each block, used only to break ties
+	// when processLoop has to salvage an irreducible SCC by picking a
+	// pseudo-header (see pickPseudoHeader).
+	rpoPos := make(map[ID]int, len(po))
+	for i, b := range po {
+		rpoPos[b.ID] = len(po) - 1 - i
+	}
+
 	if f.pass != nil && f.pass.debug > 2 {
 		fmt.Printf("loop finding (Bourdoncle) in %s\n", f.Name)
 	}
@@ -29,21 +48,19 @@ func loopnestfor(f *Func) *loopnest {
 		fmt.Printf("  found %d SCCs\n", len(sccs))
 	}
 
-	// Use cached top-level SCCs
+	// Use cached top-level SCCs. processLoop itself detects and
+	// salvages irreducible SCCs (scc.Header() == nil), so reducible and
+	// irreducible loops are handled uniformly here.
 	for i, scc := range sccs {
 		if !scc.IsLoop() {
 			continue
 		}
-		if !scc.IsReducible() {
-			sawIrred = true
-			continue
-		}
 		lscc := &sccs[i]
 		if f.pass != nil && f.pass.debug > 3 {
 			fmt.Printf("  processing loop SCC with %d blocks\n", len(lscc.Blocks))
 		}
 		// Recursively process this component
-		processLoop(f, lscc, nil, b2l, &loops, &sawIrred)
+		processLoop(f, lscc, nil, rpoPos, b2l, &loops, &sawIrred)
 	}
 
 	// Compute nesting depths
@@ -69,28 +86,39 @@ func loopnestfor(f *Func) *loopnest {
 }
 
 // processLoop recursively processes an SCC using Bourdoncle's decomposition.
-func processLoop(f *Func, scc *SCC, outer *loop, b2l []*loop, loops *[]*loop, sawIrred *bool) {
+// When scc has no single entry (scc.Header() == nil), it salvages the SCC
+// instead of abandoning it: see pickPseudoHeader.
+func processLoop(f *Func, scc *SCC, outer *loop, rpoPos map[ID]int, b2l []*loop, loops *[]*loop, sawIrred *bool) {
 	if len(scc.Blocks) == 0 {
 		return
 	}
 
 	// Determine outermost header into SCC
 	header := scc.Header()
+	irreducible := false
 	if header == nil {
-		// Irreducible or whatnot -> not processing!
+		// No single entry: salvage what we can instead of leaving
+		// every block in the SCC with b2l == nil and depth 0, which
+		// misleads downstream passes (loop rotation, code layout,
+		// likelyadjust) into treating the whole region as flat,
+		// straight-line code. Pick a pseudo-header and still build a
+		// loop record around it, tagged irreducible so consumers can
+		// opt out of transformations that need a real single entry.
 		*sawIrred = true
+		irreducible = true
+		header = pickPseudoHeader(scc.Blocks, rpoPos)
 		if f.pass != nil && f.pass.debug > 3 {
-			fmt.Printf("      header=%s (by dominance)\n", header)
+			fmt.Printf("      irreducible SCC, pseudo-header=%s\n", header)
 		}
-		return
 	}
 
 	// Create loop
 	l := &loop{
-		header:  header,
-		outer:   outer,
-		isInner: true,
-		nBlocks: 1,
+		header:      header,
+		outer:       outer,
+		isInner:     true,
+		nBlocks:     1,
+		irreducible: irreducible,
 	}
 	*loops = append(*loops, l)
 	b2l[header.ID] = l
@@ -100,6 +128,21 @@ func processLoop(f *Func, scc *SCC, outer *loop, b2l []*loop, loops *[]*loop, sa
 		outer.isInner = false
 	}
 
+	if irreducible {
+		// There's no single header to remove and recurse on the
+		// remainder the way Bourdoncle's decomposition does below,
+		// so flatten the whole SCC into this one loop record: every
+		// block gets a sane (if approximate) depth and nesting
+		// instead of none at all.
+		for _, b := range scc.Blocks {
+			if b != header {
+				b2l[b.ID] = l
+				l.nBlocks++
+			}
+		}
+		return
+	}
+
 	// Collect non-header blocks
 	remaining := make([]*Block, 0, len(scc.Blocks)-1)
 	for _, b := range scc.Blocks {
@@ -132,11 +175,9 @@ func processLoop(f *Func, scc *SCC, outer *loop, b2l []*loop, loops *[]*loop, sa
 	for i := range subSccs {
 		sub := &subSccs[i]
 		if sub.IsLoop() {
-			if !sub.IsReducible() {
-				*sawIrred = true
-			}
-			// Nested loop
-			processLoop(f, sub, l, b2l, loops, sawIrred)
+			// Nested loop; processLoop detects and salvages
+			// irreducibility itself now.
+			processLoop(f, sub, l, rpoPos, b2l, loops, sawIrred)
 		} else {
 			// Trivial SCC: blocks belong to current loop
 			for _, b := range sub.Blocks {
@@ -230,4 +271,38 @@ func (ln *loopnest) depth(b ID) int16 {
 		return l.depth
 	}
 	return 0
+}
+
+// Irreducible reports whether l is a salvaged irreducible region (see
+// processLoop's pseudo-header fallback) rather than a true
+// single-entry loop. Consumers like loop rotation and likelyadjust can
+// check this to opt out of a transformation for just this loop, instead
+// of the whole function bailing via ln.hasIrreducible.
+func (ln *loopnest) Irreducible(l *loop) bool {
+	return l.irreducible
+}
+
+// pickPseudoHeader chooses a stand-in header for an irreducible SCC:
+// the block with the most in-SCC predecessors (the block most back
+// edges already point at), breaking ties by earliest reverse-postorder
+// position so the choice is deterministic.
+func pickPseudoHeader(blocks []*Block, rpoPos map[ID]int) *Block {
+	inSCC := make(map[ID]bool, len(blocks))
+	for _, b := range blocks {
+		inSCC[b.ID] = true
+	}
+	best := blocks[0]
+	bestIn := -1
+	for _, b := range blocks {
+		in := 0
+		for _, e := range b.Preds {
+			if inSCC[e.b.ID] {
+				in++
+			}
+		}
+		if in > bestIn || (in == bestIn && rpoPos[b.ID] < rpoPos[best.ID]) {
+			best, bestIn = b, in
+		}
+	}
+	return best
 }
\ No newline at end of file
@@ -0,0 +1,166 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Range is a half-open [Start, End) span of linearized instruction
+// positions (see liveIntervals) during which a value is live.
+type Range struct {
+	Start, End int
+}
+
+// liveIntervals is the live-interval form of computeLive's output:
+// blocks are laid out via traceOrder and given a linear instruction
+// index, and every value's liveness becomes a sorted, merged list of
+// Ranges over that index instead of a per-block "live at end + distance"
+// entry. This is the representation register-pressure analysis,
+// coalescing, and a future linear-scan path want; computeLive's own
+// block-local form stays the allocator's hot path.
+type liveIntervals struct {
+	s          *regAllocState
+	order      []*Block
+	blockStart []int // linear index of block's first instruction, by Block.ID
+	total      int   // total linearized instruction count
+	ranges     map[ID][]Range
+	value      map[ID]*Value
+}
+
+// buildLiveIntervals derives a liveIntervals from s.live (computeLive's
+// output, which must already have been run) and a fresh backward scan of
+// each block's values for local def/use positions.
+func (s *regAllocState) buildLiveIntervals() *liveIntervals {
+	f := s.f
+	order := traceOrder(f)
+	blockStart := make([]int, f.NumBlocks())
+	pos := 0
+	for _, b := range order {
+		blockStart[b.ID] = pos
+		pos += len(b.Values)
+	}
+
+	li := &liveIntervals{
+		s:          s,
+		order:      order,
+		blockStart: blockStart,
+		total:      pos,
+		ranges:     map[ID][]Range{},
+		value:      map[ID]*Value{},
+	}
+
+	add := func(id ID, start, end int) {
+		if end < start {
+			end = start
+		}
+		li.ranges[id] = append(li.ranges[id], Range{start, end})
+	}
+
+	for _, b := range order {
+		base := blockStart[b.ID]
+		end := base + len(b.Values)
+
+		// Live-out at block end: s.live[b.ID]'s dist is computeLive's
+		// distance (in instructions) from end-of-block to the value's
+		// next use, so it remains live at least that far past this
+		// block in the linearized order.
+		for _, e := range s.live[b.ID] {
+			add(e.ID, base, end+int(e.dist))
+		}
+
+		// Defs and local uses, via the same backward scan processBlock
+		// does: a value's interval starts at its def and ends at its
+		// last use inside this block (or the def itself, if unused
+		// locally — cross-block extension already handled above).
+		lastUse := map[ID]int{}
+		for i := len(b.Values) - 1; i >= 0; i-- {
+			v := b.Values[i]
+			li.value[v.ID] = v
+			if s.values[v.ID].needReg {
+				defPos := base + i
+				use, ok := lastUse[v.ID]
+				if !ok {
+					use = defPos
+				}
+				add(v.ID, defPos, use)
+			}
+			for _, a := range v.Args {
+				if _, ok := lastUse[a.ID]; !ok {
+					lastUse[a.ID] = base + i
+				}
+			}
+		}
+	}
+
+	for id, rs := range li.ranges {
+		slices.SortFunc(rs, func(a, b Range) int { return cmp.Compare(a.Start, b.Start) })
+		li.ranges[id] = mergeRanges(rs)
+	}
+	return li
+}
+
+// mergeRanges merges overlapping or touching ranges in rs, which must
+// already be sorted by Start.
+func mergeRanges(rs []Range) []Range {
+	if len(rs) == 0 {
+		return rs
+	}
+	out := rs[:1]
+	for _, r := range rs[1:] {
+		last := &out[len(out)-1]
+		if r.Start <= last.End {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// Intervals returns the merged live ranges for id, in linearized
+// instruction-index order.
+func (li *liveIntervals) Intervals(id ID) []Range {
+	return li.ranges[id]
+}
+
+// LiveAt reports whether id is live at linearized position pos.
+func (li *liveIntervals) LiveAt(id ID, pos int) bool {
+	for _, r := range li.ranges[id] {
+		if pos >= r.Start && pos < r.End {
+			return true
+		}
+		if r.Start > pos {
+			break
+		}
+	}
+	return false
+}
+
+// PressureAt returns the number of values of the given register class
+// live at linearized position pos, i.e. the register pressure a
+// linear-scan allocator would see there.
+func (li *liveIntervals) PressureAt(pos int, class regClass) int {
+	count := 0
+	for id, rs := range li.ranges {
+		v := li.value[id]
+		if v == nil || li.s.regClass(v) != class {
+			continue
+		}
+		for _, r := range rs {
+			if pos >= r.Start && pos < r.End {
+				count++
+				break
+			}
+			if r.Start > pos {
+				break
+			}
+		}
+	}
+	return count
+}
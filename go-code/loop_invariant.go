@@ -0,0 +1,98 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// invariantInfo records, per loop, the set of value IDs that are loop
+// invariant: defined outside the loop body (or in the header itself) with
+// every argument also defined outside the loop. These are exactly the
+// values a real LICM pass would hoist; computeLive uses the set to avoid
+// treating them as call-clobbered inside the loop (see processBlock), so
+// the liveness distance it reports doesn't discourage the allocator from
+// keeping them in a register for the loop's whole lifetime.
+type invariantInfo struct {
+	byLoop map[*loop]map[ID]bool
+}
+
+// isInvariant reports whether id is loop invariant with respect to l. It
+// is nil-safe: a nil *invariantInfo (no loops, or detection skipped for an
+// irreducible CFG) reports false for everything.
+func (ii *invariantInfo) isInvariant(l *loop, id ID) bool {
+	if ii == nil || l == nil {
+		return false
+	}
+	return ii.byLoop[l][id]
+}
+
+// isLoopInvariant reports whether v is loop invariant in b's innermost
+// loop. It's a convenience wrapper around isInvariant for callers (e.g. a
+// future register-allocation pass that wants to bias desiredState toward
+// keeping hoistable values live across a whole loop body) that only have
+// a *Value and its defining Block to hand.
+func (s *regAllocState) isLoopInvariant(v *Value) bool {
+	if s.loopInvariants == nil {
+		return false
+	}
+	return s.loopInvariants.isInvariant(s.loopnest.b2l[v.Block.ID], v.ID)
+}
+
+// computeLoopInvariants finds, for every loop in ln, the values that are
+// invariant with respect to it: values whose defining block dominates (or
+// is) the loop header, and whose arguments are all defined in blocks
+// outside the loop body (including any nested sub-loops). This is the
+// classic LICM hoistability criterion, computed here purely for liveness
+// purposes -- nothing is actually hoisted.
+//
+// Detection is skipped for irreducible CFGs, where loopnest's dominance
+// information doesn't correspond to natural loops.
+func computeLoopInvariants(f *Func, ln *loopnest) *invariantInfo {
+	if len(ln.loops) == 0 || ln.hasIrreducible {
+		return nil
+	}
+	sdom := f.Sdom()
+	ii := &invariantInfo{byLoop: make(map[*loop]map[ID]bool, len(ln.loops))}
+	for _, l := range ln.loops {
+		marked := make(map[ID]bool)
+		for _, b := range f.Blocks {
+			if !sdom.IsAncestorEq(b, l.header) {
+				// b is not guaranteed to run before every
+				// iteration of l, so nothing defined in b can
+				// be hoisted out of l.
+				continue
+			}
+			for _, v := range b.Values {
+				if valueInvariantArgs(ln, l, v) {
+					marked[v.ID] = true
+				}
+			}
+		}
+		if len(marked) > 0 {
+			ii.byLoop[l] = marked
+		}
+	}
+	return ii
+}
+
+// valueInvariantArgs reports whether every argument of v is defined in a
+// block outside l's body (v's own block is assumed to already dominate
+// l's header; the caller checks that).
+func valueInvariantArgs(ln *loopnest, l *loop, v *Value) bool {
+	for _, a := range v.Args {
+		if loopContains(ln, l, a.Block) {
+			return false
+		}
+	}
+	return true
+}
+
+// loopContains reports whether b lies within l's body, including any
+// loop nested inside l.
+func loopContains(ln *loopnest, l *loop, b *Block) bool {
+	for cur := ln.b2l[b.ID]; cur != nil; cur = cur.outer {
+		if cur == l {
+			return true
+		}
+	}
+	return false
+}
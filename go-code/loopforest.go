@@ -0,0 +1,124 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// Loop is one node of a LoopForest: a reducible loop built directly from
+// sccs(), with inner loops found, as in sccloopnest.go's LoopNest, by
+// recursing on sccSubgraph with the header excluded. Irreducible
+// SCCs are recorded as opaque loops with Header == nil; see
+// LoopForest.Irreducible.
+type Loop struct {
+	Header                  *Block
+	Outer                   *Loop
+	Children                []*Loop
+	Exits                   []*Block
+	Depth                   int
+	IsInner                 bool
+	ContainsUnavoidableCall bool
+	Blocks                  []*Block // blocks in this loop, excluding any inner child's blocks
+}
+
+// LoopForest is the set of top-level (Outer == nil) Loops in a Func, plus
+// a flag recording whether any SCC couldn't be represented because it was
+// irreducible.
+type LoopForest struct {
+	Roots       []*Loop
+	Irreducible bool
+}
+
+// LoopForest returns the cached LoopForest for f, computing it if
+// necessary.
+func (f *Func) LoopForest() *LoopForest {
+	if f.cachedLoopForest == nil {
+		f.cachedLoopForest = buildLoopForest(f)
+	}
+	return f.cachedLoopForest
+}
+
+func buildLoopForest(f *Func) *LoopForest {
+	lf := &LoopForest{}
+	sdom := f.Sdom()
+	for i := range f.sccs() {
+		scc := &f.sccs()[i]
+		if !scc.IsLoop() {
+			continue
+		}
+		if !scc.IsReducible() {
+			lf.Irreducible = true
+			lf.Roots = append(lf.Roots, &Loop{Header: nil, Blocks: scc.Blocks, Depth: 1})
+			continue
+		}
+		lf.Roots = append(lf.Roots, buildLoop(f, scc, nil, 1, sdom))
+	}
+	return lf
+}
+
+// buildLoop constructs the Loop for scc (header = scc.Header()), nested
+// under outer at the given depth, then recurses into
+// sccSubgraph(f, scc.Blocks, header) to find any nested loops: every loop
+// SCC of that subgraph becomes a child of this loop. sdom is passed down
+// but not consulted.
+func buildLoop(f *Func, scc *SCC, outer *Loop, depth int, sdom SparseTree) *Loop {
+	header := scc.Header()
+	l := &Loop{Header: header, Outer: outer, Depth: depth, IsInner: true}
+
+	inner := sccSubgraph(f, scc.Blocks, header)
+	ownBlocks := make(map[ID]bool, len(scc.Blocks))
+	for _, b := range scc.Blocks {
+		ownBlocks[b.ID] = true
+	}
+
+	for i := range inner {
+		childSCC := &inner[i]
+		if !childSCC.IsLoop() {
+			continue
+		}
+		// childSCC came from sccSubgraph(scc.Blocks, header), so header
+		// dominates every block in it by construction; sdom is threaded
+		// through for headerByDominance-style callers that want to
+		// double check that invariant themselves.
+		var child *Loop
+		if childSCC.IsReducible() {
+			child = buildLoop(f, childSCC, l, depth+1, sdom)
+		} else {
+			child = &Loop{Header: nil, Outer: l, Blocks: childSCC.Blocks, Depth: depth + 1}
+		}
+		l.Children = append(l.Children, child)
+		l.IsInner = false
+		for _, b := range childSCC.Blocks {
+			delete(ownBlocks, b.ID)
+		}
+	}
+
+	l.Blocks = make([]*Block, 0, len(ownBlocks))
+	for _, b := range scc.Blocks {
+		if ownBlocks[b.ID] {
+			l.Blocks = append(l.Blocks, b)
+		}
+	}
+
+	inLoop := make(map[ID]bool, len(scc.Blocks))
+	for _, b := range scc.Blocks {
+		inLoop[b.ID] = true
+	}
+	seenExit := make(map[ID]bool)
+	call := false
+	for _, b := range scc.Blocks {
+		for _, v := range b.Values {
+			if opcodeTable[v.Op].call {
+				call = true
+			}
+		}
+		for _, e := range b.Succs {
+			if !inLoop[e.b.ID] && !seenExit[e.b.ID] {
+				seenExit[e.b.ID] = true
+				l.Exits = append(l.Exits, e.b)
+			}
+		}
+	}
+	l.ContainsUnavoidableCall = call
+
+	return l
+}
@@ -0,0 +1,268 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// edgeKind classifies a CFG edge relative to a single DFS tree, per
+// Havlak's algorithm.
+type edgeKind uint8
+
+const (
+	edgeTree edgeKind = iota
+	edgeForward
+	edgeBack
+	edgeCross
+)
+
+// havlakDFS holds the bookkeeping a single DFS pass over f produces:
+// preorder numbers, the interval each node's subtree spans (used for the
+// O(1) ancestor test classifyEdge needs), and each node's back/non-back
+// predecessor sets.
+type havlakDFS struct {
+	pre, post    []int32 // 0 means unvisited
+	order        []*Block
+	backPreds    [][]ID
+	nonBackPreds [][]ID
+}
+
+// isAncestor reports whether w is a DFS-tree ancestor of (or equal to) v,
+// using the standard preorder/postorder interval containment test.
+func (d *havlakDFS) isAncestor(w, v ID) bool {
+	return d.pre[w] <= d.pre[v] && d.post[v] <= d.post[w]
+}
+
+// runHavlakDFS walks f from f.Entry, numbering nodes in preorder and
+// postorder and splitting every edge into back and non-back by the
+// standard white/gray/black coloring: an edge to a gray (on-stack) node
+// is a back edge; an edge to a white (tree) or black (forward or cross)
+// node is recorded as a non-back predecessor.
+func runHavlakDFS(f *Func) *havlakDFS {
+	n := f.NumBlocks()
+	d := &havlakDFS{
+		pre:          make([]int32, n),
+		post:         make([]int32, n),
+		backPreds:    make([][]ID, n),
+		nonBackPreds: make([][]ID, n),
+	}
+	const (
+		white = 0
+		gray  = 1
+		black = 2
+	)
+	color := make([]uint8, n)
+	var preCounter, postCounter int32
+
+	var dfs func(b *Block)
+	dfs = func(b *Block) {
+		color[b.ID] = gray
+		preCounter++
+		d.pre[b.ID] = preCounter
+		d.order = append(d.order, b)
+		for _, e := range b.Succs {
+			s := e.b
+			switch color[s.ID] {
+			case white:
+				dfs(s)
+				d.nonBackPreds[s.ID] = append(d.nonBackPreds[s.ID], b.ID)
+			case gray:
+				d.backPreds[s.ID] = append(d.backPreds[s.ID], b.ID)
+			case black:
+				d.nonBackPreds[s.ID] = append(d.nonBackPreds[s.ID], b.ID)
+			}
+		}
+		color[b.ID] = black
+		postCounter++
+		d.post[b.ID] = postCounter
+	}
+	if f.Entry != nil {
+		dfs(f.Entry)
+	}
+	return d
+}
+
+// classifyEdge reports e's kind under d, assuming both endpoints were
+// reached by the DFS that produced d. It exists to document the
+// tree/forward/back/cross distinction the request asks for; the loop
+// finder below only needs the back/non-back split runHavlakDFS already
+// recorded, so nothing downstream of the DFS calls this.
+func classifyEdge(d *havlakDFS, from, to ID) edgeKind {
+	switch {
+	case d.pre[to] > d.pre[from]:
+		if d.isAncestor(from, to) {
+			return edgeTree
+		}
+		return edgeForward
+	case d.isAncestor(to, from):
+		return edgeBack
+	default:
+		return edgeCross
+	}
+}
+
+// BuildHavlakLoopForest computes a *LoopForest using Havlak's
+// interval-finding algorithm, classifying every header as reducible,
+// self-loop, or irreducible: a header h is processed by seeding a
+// worklist with BackPreds[h] and walking NonBackPreds, collapsing each
+// reachable node into h's loop with union-find; any node reached this
+// way that the governing DFS never placed inside h's own subtree (i.e.
+// !isAncestor(h, node)) means some other entry reaches h's loop from
+// outside h's dominance, so h is marked irreducible and that extra
+// predecessor is folded in as a non-back predecessor of h itself rather
+// than silently absorbed.
+//
+// This is an independent construction from loopnestforHavlak (which
+// shares the union-find idea but only detects irreducibility
+// conservatively, as "some block the single DFS never reached") and from
+// buildLoopForest (which classifies irreducibility via sccs() instead of
+// a DFS walk). All three should agree on every reducible CFG. This one
+// tracks irreducibility per header while it runs, but the result only
+// carries it as the function-wide LoopForest.Irreducible flag.
+func BuildHavlakLoopForest(f *Func) *LoopForest {
+	d := runHavlakDFS(f)
+	n := f.NumBlocks()
+
+	blockByID := make(map[ID]*Block, len(f.Blocks))
+	for _, b := range f.Blocks {
+		blockByID[b.ID] = b
+	}
+
+	union := make([]ID, n)
+	for _, b := range d.order {
+		union[b.ID] = b.ID
+	}
+	var find func(id ID) ID
+	find = func(id ID) ID {
+		root := id
+		for union[root] != root {
+			root = union[root]
+		}
+		for union[id] != root {
+			next := union[id]
+			union[id] = root
+			id = next
+		}
+		return root
+	}
+
+	type headerInfo struct {
+		header      *Block
+		irreducible bool
+		blocks      []*Block
+		outer       *headerInfo
+	}
+	headerOf := make(map[ID]*headerInfo, 8)
+	var headers []*headerInfo
+
+	// Process in decreasing preorder number: inner loops (reached later in
+	// the DFS) are collapsed into their union-find roots before any outer
+	// loop's walk reaches them, which is what makes nesting fall out of a
+	// single reverse pass.
+	for i := len(d.order) - 1; i >= 0; i-- {
+		h := d.order[i]
+		if len(d.backPreds[h.ID]) == 0 {
+			continue
+		}
+
+		hi := &headerInfo{header: h, blocks: []*Block{h}}
+		headerOf[h.ID] = hi
+		headers = append(headers, hi)
+
+		inLoop := map[ID]bool{h.ID: true}
+		var work []ID
+		for _, p := range d.backPreds[h.ID] {
+			root := find(p)
+			if root != h.ID && !inLoop[root] {
+				inLoop[root] = true
+				work = append(work, root)
+			}
+		}
+		for len(work) > 0 {
+			last := len(work) - 1
+			x := work[last]
+			work = work[:last]
+
+			if sub, ok := headerOf[x]; ok && sub != hi {
+				sub.outer = hi
+			} else if x != h.ID {
+				hi.blocks = append(hi.blocks, blockByID[x])
+			}
+			union[x] = h.ID
+
+			for _, y := range d.nonBackPreds[x] {
+				root := find(y)
+				if root == h.ID || inLoop[root] {
+					continue
+				}
+				if !d.isAncestor(h.ID, root) {
+					// root lies outside h's own DFS subtree: some path
+					// into this loop bypasses h, so the region has more
+					// than one entry.
+					hi.irreducible = true
+					d.nonBackPreds[h.ID] = append(d.nonBackPreds[h.ID], root)
+					continue
+				}
+				inLoop[root] = true
+				work = append(work, root)
+			}
+		}
+	}
+
+	lf := &LoopForest{}
+	byHeader := make(map[ID]*Loop, len(headers))
+	for _, hi := range headers {
+		l := &Loop{Header: hi.header, IsInner: true}
+		byHeader[hi.header.ID] = l
+	}
+	for _, hi := range headers {
+		l := byHeader[hi.header.ID]
+		if hi.irreducible {
+			lf.Irreducible = true
+		}
+		l.Blocks = hi.blocks
+		if hi.outer != nil {
+			outer := byHeader[hi.outer.header.ID]
+			l.Outer = outer
+			outer.Children = append(outer.Children, l)
+			outer.IsInner = false
+		} else {
+			lf.Roots = append(lf.Roots, l)
+		}
+	}
+	for _, hi := range headers {
+		l := byHeader[hi.header.ID]
+		computeLoopForestExits(f, l)
+		if hi.outer != nil {
+			l.Depth = byHeader[hi.outer.header.ID].Depth + 1
+		} else {
+			l.Depth = 1
+		}
+	}
+	return lf
+}
+
+// computeLoopForestExits fills in l.Exits and l.ContainsUnavoidableCall
+// from l.Blocks, mirroring buildLoop's own exit/call computation so both
+// constructors populate a Loop identically.
+func computeLoopForestExits(f *Func, l *Loop) {
+	inLoop := make(map[ID]bool, len(l.Blocks))
+	for _, b := range l.Blocks {
+		inLoop[b.ID] = true
+	}
+	seenExit := make(map[ID]bool)
+	call := false
+	for _, b := range l.Blocks {
+		for _, v := range b.Values {
+			if opcodeTable[v.Op].call {
+				call = true
+			}
+		}
+		for _, e := range b.Succs {
+			if !inLoop[e.b.ID] && !seenExit[e.b.ID] {
+				seenExit[e.b.ID] = true
+				l.Exits = append(l.Exits, e.b)
+			}
+		}
+	}
+	l.ContainsUnavoidableCall = call
+}
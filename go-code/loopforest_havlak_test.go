@@ -0,0 +1,86 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import "testing"
+
+// TestHavlakLoopForestReducible checks BuildHavlakLoopForest against
+// buildHeapSortCFG and buildFloatPrecCFG, two real-function CFGs that
+// are fully reducible: no loop should be marked irreducible, and the
+// overall forest should agree with buildLoopForest's SCC-based
+// construction on which blocks belong to which loop header.
+func TestHavlakLoopForestReducible(t *testing.T) {
+	cases := []struct {
+		name string
+		f    func(t *testing.T) *Func
+	}{
+		{"heapsort", func(t *testing.T) *Func { return buildHeapSortCFG(testConfig(t)) }},
+		{"floatprec", func(t *testing.T) *Func { return buildFloatPrecCFG(testConfig(t)) }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := c.f(t)
+
+			havlak := BuildHavlakLoopForest(f)
+			if havlak.Irreducible {
+				t.Errorf("BuildHavlakLoopForest marked a reducible CFG irreducible")
+			}
+			scc := buildLoopForest(f)
+			if scc.Irreducible {
+				t.Fatalf("test CFG %s unexpectedly has an irreducible SCC per buildLoopForest", c.name)
+			}
+
+			header := func(forest *LoopForest) map[ID]ID {
+				m := make(map[ID]ID)
+				var walk func(l *Loop)
+				walk = func(l *Loop) {
+					for _, b := range l.Blocks {
+						m[b.ID] = l.Header.ID
+					}
+					for _, child := range l.Children {
+						walk(child)
+					}
+				}
+				for _, l := range forest.Roots {
+					walk(l)
+				}
+				return m
+			}
+
+			hm, sm := header(havlak), header(scc)
+			if len(hm) != len(sm) {
+				t.Errorf("loop-member count mismatch: havlak=%d scc=%d", len(hm), len(sm))
+			}
+			for id, h := range sm {
+				if hm[id] != h {
+					t.Errorf("block %v: scc assigns header %v, havlak assigns %v", id, h, hm[id])
+				}
+			}
+		})
+	}
+}
+
+// TestHavlakLoopForestIrreducible checks that BuildHavlakLoopForest
+// reports irreducibility (LoopForest.Irreducible) for
+// buildIrreducibleWithReducible (one reducible loop around
+// one irreducible region) and buildIrreducibleChain (several consecutive
+// irreducible regions).
+func TestHavlakLoopForestIrreducible(t *testing.T) {
+	t.Run("mixed", func(t *testing.T) {
+		f := buildIrreducibleWithReducible(t, 4)
+		forest := BuildHavlakLoopForest(f)
+		if !forest.Irreducible {
+			t.Fatalf("expected Irreducible == true for a CFG with an irreducible region")
+		}
+	})
+	t.Run("chain", func(t *testing.T) {
+		f := buildIrreducibleChain(t, 3)
+		forest := BuildHavlakLoopForest(f)
+		if !forest.Irreducible {
+			t.Fatalf("expected Irreducible == true for a chain of irreducible regions")
+		}
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// loopInvariants maps a value ID to the innermost loop containing its
+// block, for values invariant with respect to that loop: every one of
+// its Args is defined outside that loop (or is itself invariant w.r.t.
+// it), and it is neither a Phi nor a call. Outer loops the value may
+// also be invariant in are not recorded, and other ops with side effects
+// are not excluded. It is
+// computed once per regAllocState and consulted by propagateLoopLiveness
+// and computeDesired to bias liveness distance and register preference
+// toward keeping loop-invariant values resident across the whole loop,
+// borrowing the classic LICM legality test without doing any actual code
+// motion.
+type loopInvariants map[ID]*loop
+
+// isInsideLoop reports whether al (the innermost loop containing some
+// block, possibly nil for top-level blocks) is l itself or nested inside
+// l.
+func isInsideLoop(al, l *loop) bool {
+	for x := al; x != nil; x = x.outer {
+		if x == l {
+			return true
+		}
+	}
+	return false
+}
+
+// computeLoopInvariants finds every value defined inside a loop that is
+// invariant with respect to its block's innermost loop, and records that
+// loop for it. Values defined
+// outside every loop are not recorded; they are trivially invariant
+// everywhere and don't need the special liveness/desired-register
+// treatment this analysis exists for.
+func (s *regAllocState) computeLoopInvariants() loopInvariants {
+	inv := loopInvariants{}
+	if s.loopnest == nil || len(s.loopnest.loops) == 0 {
+		return inv
+	}
+	for _, b := range s.f.Blocks {
+		l := s.loopnest.b2l[b.ID]
+		if l == nil {
+			continue
+		}
+		for _, v := range b.Values {
+			if v.Op == OpPhi || opcodeTable[v.Op].call {
+				// Phis carry the loop-varying value by construction;
+				// calls are never treated as side-effect free here.
+				continue
+			}
+			invariant := true
+			for _, a := range v.Args {
+				al := s.loopnest.b2l[a.Block.ID]
+				if isInsideLoop(al, l) && inv[a.ID] != l {
+					invariant = false
+					break
+				}
+			}
+			if invariant {
+				inv[v.ID] = l
+			}
+		}
+	}
+	return inv
+}
+
+// preheader returns the loop's unique predecessor block that lies
+// outside the loop (and outside any of its nested children), or nil if
+// there isn't exactly one such block (e.g. the loop has multiple
+// external entries, or none of its header's predecessors are external).
+func (l *loop) preheader(ln *loopnest) *Block {
+	var ph *Block
+	for _, e := range l.header.Preds {
+		p := e.b
+		if isInsideLoop(ln.b2l[p.ID], l) {
+			continue
+		}
+		if ph != nil {
+			return nil
+		}
+		ph = p
+	}
+	return ph
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// AnalyzeLoops is an alternate, free-function entry point onto the
+// SCC-derived LoopNest (sccloopnest.go) for callers that would rather
+// not reach through f.Loops()'s method-call/caching spelling.
+// LoopNest.Loops (the field, populated innermost-first) already serves
+// as its own Loops() accessor, so no method is added here under that
+// name -- a field and a method can't share an identifier on the same
+// type.
+func AnalyzeLoops(f *Func) *LoopNest {
+	return f.Loops()
+}
+
+// Contains reports whether b belongs to l, including blocks that belong
+// to one of l's nested child loops.
+func (l *Loop) Contains(b *Block) bool {
+	for _, lb := range l.Blocks {
+		if lb == b {
+			return true
+		}
+	}
+	return false
+}
+
+// BackEdges returns l's backedges: intra-loop edges whose target is l's
+// header. Same data as the Backedges field, spelled out as a method to
+// pair with ExitEdges below.
+func (l *Loop) BackEdges() []Edge {
+	return l.Backedges
+}
+
+// ExitEdges returns the edges leaving l. Same data as the Exits field.
+func (l *Loop) ExitEdges() []Edge {
+	return l.Exits
+}
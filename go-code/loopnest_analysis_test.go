@@ -0,0 +1,55 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import "testing"
+
+// TestAnalyzeLoopsNested checks AnalyzeLoops/Contains/BackEdges/ExitEdges
+// against buildNestedLoops(t, 3): every loop should contain its own
+// header, have at least one backedge, have every backedge target its
+// header, and have no exit edge that targets a block inside the loop.
+func TestAnalyzeLoopsNested(t *testing.T) {
+	f := buildNestedLoops(t, 3)
+	nest := AnalyzeLoops(f)
+
+	if len(nest.Loops) != 3 {
+		t.Fatalf("expected 3 loops, got %d", len(nest.Loops))
+	}
+	for _, l := range nest.Loops {
+		if !l.Contains(l.Header) {
+			t.Errorf("loop headed by %s does not Contain its own header", l.Header)
+		}
+		if len(l.BackEdges()) == 0 {
+			t.Errorf("loop headed by %s has no backedges", l.Header)
+		}
+		for _, e := range l.BackEdges() {
+			if e.b != l.Header {
+				t.Errorf("backedge %v of loop %s does not target the header", e, l.Header)
+			}
+		}
+		for _, e := range l.ExitEdges() {
+			if l.Contains(e.b) {
+				t.Errorf("exit edge %v of loop %s targets a block still inside the loop", e, l.Header)
+			}
+		}
+	}
+}
+
+// TestAnalyzeLoopsIrreducible checks that AnalyzeLoops marks a
+// multi-entry SCC Irreducible.
+func TestAnalyzeLoopsIrreducible(t *testing.T) {
+	f := buildIrreducibleMultiEntry(t, 3)
+	nest := AnalyzeLoops(f)
+
+	irred := nest.Irreducible()
+	if len(irred) == 0 {
+		t.Fatalf("expected at least one irreducible loop, found none")
+	}
+	for _, l := range irred {
+		if !l.Irreducible {
+			t.Errorf("Irreducible() returned a loop with Irreducible == false")
+		}
+	}
+}
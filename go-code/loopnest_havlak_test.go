@@ -0,0 +1,92 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+// TestHavlakMatchesBourdoncle cross-validates loopnestforHavlak against
+// loopnestfor (Bourdoncle) on a handful of reducible CFGs: a single
+// loop, a nested loop, and two sibling loops in sequence (the first
+// exits straight into the second). Both
+// finders must agree on which loop (by header block) owns each block.
+func TestHavlakMatchesBourdoncle(t *testing.T) {
+	type testCase struct {
+		name string
+		fut  func(t *testing.T) fun
+	}
+
+	cases := []testCase{
+		{"single", func(t *testing.T) fun {
+			c := testConfig(t)
+			return c.Fun("entry",
+				Bloc("entry", Valu("mem", OpInitMem, types.TypeMem, 0, nil), Goto("loop")),
+				Bloc("loop",
+					Valu("cond", OpConstBool, c.config.Types.Bool, 1, nil),
+					If("cond", "loop", "exit")),
+				Bloc("exit", Exit("mem")))
+		}},
+		{"nested", func(t *testing.T) fun {
+			c := testConfig(t)
+			return c.Fun("entry",
+				Bloc("entry", Valu("mem", OpInitMem, types.TypeMem, 0, nil), Goto("outer")),
+				Bloc("outer",
+					Valu("cond1", OpConstBool, c.config.Types.Bool, 1, nil),
+					If("cond1", "inner", "exit")),
+				Bloc("inner",
+					Valu("cond2", OpConstBool, c.config.Types.Bool, 1, nil),
+					If("cond2", "inner", "outer")),
+				Bloc("exit", Exit("mem")))
+		}},
+		{"siblings", func(t *testing.T) fun {
+			c := testConfig(t)
+			return c.Fun("entry",
+				Bloc("entry", Valu("mem", OpInitMem, types.TypeMem, 0, nil), Goto("loop1")),
+				Bloc("loop1",
+					Valu("cond1", OpConstBool, c.config.Types.Bool, 1, nil),
+					If("cond1", "loop1", "loop2")),
+				Bloc("loop2",
+					Valu("cond2", OpConstBool, c.config.Types.Bool, 1, nil),
+					If("cond2", "loop2", "exit")),
+				Bloc("exit", Exit("mem")))
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fut := tc.fut(t)
+			f := fut.f
+
+			bourdoncle := loopnestfor(f)
+			havlak := loopnestforHavlak(f)
+
+			if len(bourdoncle.loops) != len(havlak.loops) {
+				t.Fatalf("loop count mismatch: bourdoncle=%d havlak=%d", len(bourdoncle.loops), len(havlak.loops))
+			}
+
+			headerName := func(l *loop) string {
+				if l == nil {
+					return "<nil>"
+				}
+				for n, b := range fut.blocks {
+					if b == l.header {
+						return n
+					}
+				}
+				return "?"
+			}
+
+			for _, b := range f.Blocks {
+				bHeader := headerName(bourdoncle.b2l[b.ID])
+				hHeader := headerName(havlak.b2l[b.ID])
+				if bHeader != hHeader {
+					t.Errorf("block %s: bourdoncle assigns loop header %s, havlak assigns %s", b, bHeader, hHeader)
+				}
+			}
+		})
+	}
+}
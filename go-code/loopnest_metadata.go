@@ -0,0 +1,147 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// This file fleshes out *loop with the children/exits/call-cost metadata
+// register allocation and spill placement need: l.children (direct
+// sub-loops), l.exits (successor blocks reached by leaving l), and
+// l.containsUnavoidableCall (every header-to-backedge path runs through
+// a call). Each is computed lazily, on first access through the
+// loopnest accessor, so callers that never ask for it don't pay for it.
+
+// children returns l's direct sub-loops, computing the whole loopnest's
+// child lists on first call.
+func (ln *loopnest) children(l *loop) []*loop {
+	if !ln.childrenComputed {
+		ln.assembleChildren()
+	}
+	return l.children
+}
+
+// exits returns the blocks l can transfer control to by leaving the
+// loop, computing the whole loopnest's exit lists on first call.
+func (ln *loopnest) exits(l *loop) []*Block {
+	if !ln.exitsComputed {
+		ln.findExits()
+	}
+	return l.exits
+}
+
+// ContainsUnavoidableCall reports whether every path from l's header to
+// one of its back edges passes through a block containing a call,
+// computing containsUnavoidableCall for the whole loopnest on first
+// call.
+func (ln *loopnest) ContainsUnavoidableCall(l *loop) bool {
+	if !ln.callsComputed {
+		ln.findCalls()
+	}
+	return l.containsUnavoidableCall
+}
+
+// assembleChildren populates l.children for every loop in ln from the
+// existing l.outer pointers.
+func (ln *loopnest) assembleChildren() {
+	for _, l := range ln.loops {
+		if l.outer != nil {
+			l.outer.children = append(l.outer.children, l)
+		}
+	}
+	ln.childrenComputed = true
+}
+
+// findExits populates l.exits for every loop in ln: for each block
+// directly owned by l (ln.b2l[b.ID] == l), any successor owned by a
+// different loop (including no loop at all) is an exit target.
+//
+// This only considers blocks whose innermost loop is l itself, matching
+// how b2l is keyed; a nested sub-loop's own exits are recorded against
+// that sub-loop, not l, even if they also happen to leave l.
+func (ln *loopnest) findExits() {
+	for _, b := range ln.f.Blocks {
+		l := ln.b2l[b.ID]
+		if l == nil {
+			continue
+		}
+		for _, e := range b.Succs {
+			if ln.b2l[e.b.ID] != l {
+				l.exits = appendBlockUnique(l.exits, e.b)
+			}
+		}
+	}
+	ln.exitsComputed = true
+}
+
+func appendBlockUnique(blocks []*Block, b *Block) []*Block {
+	for _, x := range blocks {
+		if x == b {
+			return blocks
+		}
+	}
+	return append(blocks, b)
+}
+
+// findCalls sets containsUnavoidableCall for every loop in ln.
+//
+// A loop's header is unavoidable-call-free if there is some path from
+// the header to one of the loop's back edges that never runs through a
+// block containing a call. findCalls looks for such a path with a
+// worklist reverse-reachability walk seeded at every call-free back-edge
+// source, walking predecessors within the loop body and refusing to
+// cross a block that contains a call; if that walk ever reaches the
+// header, a call-free path exists and containsUnavoidableCall is false.
+// Otherwise every path is blocked by a call, and it's true.
+func (ln *loopnest) findCalls() {
+	for _, l := range ln.loops {
+		l.containsUnavoidableCall = loopAlwaysCalls(ln, l)
+	}
+	ln.callsComputed = true
+}
+
+func loopAlwaysCalls(ln *loopnest, l *loop) bool {
+	if blockHasCall(l.header) {
+		return true
+	}
+
+	reached := map[ID]bool{}
+	var work []*Block
+	for _, b := range ln.f.Blocks {
+		if !loopContains(ln, l, b) || blockHasCall(b) {
+			continue
+		}
+		for _, e := range b.Succs {
+			if e.b == l.header && !reached[b.ID] {
+				reached[b.ID] = true
+				work = append(work, b)
+			}
+		}
+	}
+
+	for len(work) > 0 {
+		n := len(work) - 1
+		b := work[n]
+		work = work[:n]
+		if b == l.header {
+			return false // found a call-free header-to-backedge path
+		}
+		for _, e := range b.Preds {
+			p := e.b
+			if !loopContains(ln, l, p) || blockHasCall(p) || reached[p.ID] {
+				continue
+			}
+			reached[p.ID] = true
+			work = append(work, p)
+		}
+	}
+	return true
+}
+
+func blockHasCall(b *Block) bool {
+	for _, v := range b.Values {
+		if opcodeTable[v.Op].call {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// LoopProfileRecord is one loop's entry in a LoopnestProfile: enough to
+// let an offline tool (pprof/havlak-style) rank loops by depth, size,
+// and whether they're worth manually optimizing.
+type LoopProfileRecord struct {
+	Func                string
+	Header              ID
+	Depth               int16
+	NBlocks             int
+	IsInner             bool
+	Irreducible         bool
+	ExitCount           int
+	ContainsUnavoidable bool
+	EstimatedTripCount  float64
+}
+
+// LoopnestProfile is the structured form of logLoopStats: instead of
+// one f.LogStat call per loop, it's a value callers can accumulate
+// across functions and serialize.
+type LoopnestProfile struct {
+	Func    string
+	Records []LoopProfileRecord
+}
+
+// Profile builds a LoopnestProfile for ln, one record per loop, using
+// the children/exits/call-cost metadata from loopnest_metadata.go and a
+// branch-prediction-based trip-count estimate (see estimateTripCount).
+func (ln *loopnest) Profile() LoopnestProfile {
+	p := LoopnestProfile{Func: ln.f.Name, Records: make([]LoopProfileRecord, 0, len(ln.loops))}
+	for _, l := range ln.loops {
+		p.Records = append(p.Records, LoopProfileRecord{
+			Func:                ln.f.Name,
+			Header:              l.header.ID,
+			Depth:               l.depth,
+			NBlocks:             l.nBlocks,
+			IsInner:             l.isInner,
+			Irreducible:         ln.Irreducible(l),
+			ExitCount:           len(ln.exits(l)),
+			ContainsUnavoidable: ln.ContainsUnavoidableCall(l),
+			EstimatedTripCount:  estimateTripCount(ln, l),
+		})
+	}
+	return p
+}
+
+// WriteJSON serializes p to w. This is the hand-off point a
+// cmd/compile-level integration would use to accumulate one
+// LoopnestProfile per function and flush them to the path named by a
+// "-d=ssa/loopprofile=path" debug flag at the end of compilation, and
+// that a "go tool" summarizer (top-N deepest nests, call-free loops
+// above a size threshold, etc.) would read back in. Neither of those
+// exists in this snapshot -- it contains only
+// cmd/compile/internal/ssa, not the cmd/compile driver or a cmd/
+// tool tree -- so this function is as far as the hook goes here.
+func (p LoopnestProfile) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	return enc.Encode(p)
+}
+
+const (
+	estimatedTripCountUnlikely = 1.5
+	estimatedTripCountNormal   = 4.0
+	estimatedTripCountLikely   = 10.0
+)
+
+// estimateTripCount heuristically estimates l's trip count from the
+// branch-prediction hints (Block.Likely) on its back edges: a backedge
+// the branch predictor marks likely suggests the loop usually keeps
+// iterating, so trip count is nudged up toward
+// estimatedTripCountLikely; an unlikely backedge suggests the opposite.
+// With no prediction data at all, or conflicting data from multiple
+// back edges, it falls back to estimatedTripCountNormal. This is a
+// coarse heuristic for ranking loops in a profile dump, not a real
+// trip-count analysis.
+func estimateTripCount(ln *loopnest, l *loop) float64 {
+	sawLikely, sawUnlikely := false, false
+	for _, e := range l.header.Preds {
+		p := e.b
+		if !loopContains(ln, l, p) {
+			continue
+		}
+		if len(p.Succs) != 2 {
+			continue
+		}
+		for i, se := range p.Succs {
+			if se.b != l.header {
+				continue
+			}
+			if i == 0 && p.Likely == BranchLikely || i == 1 && p.Likely == BranchUnlikely {
+				sawLikely = true
+			}
+			if i == 0 && p.Likely == BranchUnlikely || i == 1 && p.Likely == BranchLikely {
+				sawUnlikely = true
+			}
+		}
+	}
+	switch {
+	case sawLikely && !sawUnlikely:
+		return estimatedTripCountLikely
+	case sawUnlikely && !sawLikely:
+		return estimatedTripCountUnlikely
+	}
+	return estimatedTripCountNormal
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// NaturalLoop is a loop found the classical way: from a single backedge
+// b -> h where h dominates b, rather than from an SCC. Loops that share a
+// header (multiple backedges into the same block) are merged into one
+// NaturalLoop with all their backedges and the union of their bodies.
+type NaturalLoop struct {
+	Header    *Block
+	Backedges []Edge
+	Body      []*Block
+}
+
+// Preheader returns Header's unique predecessor outside the loop body, or
+// nil if there isn't exactly one.
+func (l *NaturalLoop) Preheader() *Block {
+	inBody := make(map[ID]bool, len(l.Body))
+	for _, b := range l.Body {
+		inBody[b.ID] = true
+	}
+	var ph *Block
+	for _, e := range l.Header.Preds {
+		if inBody[e.b.ID] {
+			continue
+		}
+		if ph != nil {
+			return nil
+		}
+		ph = e.b
+	}
+	return ph
+}
+
+// NaturalLoops finds every natural loop in f: for every edge b -> h where
+// h dominates b (per f.Sdom()), h is a loop header and the loop's body is
+// every block that can reach b without passing through h. Natural-loop
+// detection is cheaper than SCC construction and is what LICM and
+// induction-variable analysis normally want; passes that don't care about
+// irreducible loops can use this and skip sccs() entirely.
+func (f *Func) NaturalLoops() []NaturalLoop {
+	sdom := f.Sdom()
+	byHeader := map[ID]*NaturalLoop{}
+	var order []ID
+	for _, b := range f.Blocks {
+		for _, e := range b.Succs {
+			h := e.b
+			if !sdom.IsAncestorEq(h, b) {
+				continue
+			}
+			nl, ok := byHeader[h.ID]
+			if !ok {
+				nl = &NaturalLoop{Header: h}
+				byHeader[h.ID] = nl
+				order = append(order, h.ID)
+			}
+			nl.Backedges = append(nl.Backedges, e)
+			nl.Body = unionBlocks(nl.Body, naturalLoopBody(h, b))
+		}
+	}
+	out := make([]NaturalLoop, len(order))
+	for i, id := range order {
+		out[i] = *byHeader[id]
+	}
+	return out
+}
+
+// naturalLoopBody returns h plus every block that can reach b by walking
+// predecessors without passing through h.
+func naturalLoopBody(h, b *Block) []*Block {
+	body := []*Block{h}
+	if b == h {
+		return body
+	}
+	seen := map[ID]bool{h.ID: true, b.ID: true}
+	body = append(body, b)
+	stack := []*Block{b}
+	for len(stack) > 0 {
+		x := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range x.Preds {
+			p := e.b
+			if seen[p.ID] {
+				continue
+			}
+			seen[p.ID] = true
+			body = append(body, p)
+			stack = append(stack, p)
+		}
+	}
+	return body
+}
+
+func unionBlocks(a, b []*Block) []*Block {
+	seen := make(map[ID]bool, len(a))
+	out := append([]*Block(nil), a...)
+	for _, x := range a {
+		seen[x.ID] = true
+	}
+	for _, x := range b {
+		if !seen[x.ID] {
+			seen[x.ID] = true
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// VerifyLoopAgreement cross-checks NaturalLoops against sccs(): for every
+// reducible loop SCC, the natural loop sharing its header must have the
+// same body. It returns one diagnostic string per disagreement found, so
+// CheckFunc can report all of them instead of bailing out on the first.
+func VerifyLoopAgreement(f *Func) []string {
+	var problems []string
+	natural := f.NaturalLoops()
+	byHeader := make(map[ID]*NaturalLoop, len(natural))
+	for i := range natural {
+		byHeader[natural[i].Header.ID] = &natural[i]
+	}
+
+	sccs := f.sccs()
+	for i := range sccs {
+		scc := &sccs[i]
+		if !scc.IsLoop() || !scc.IsReducible() {
+			continue
+		}
+		header := scc.Header()
+		nl, ok := byHeader[header.ID]
+		if !ok {
+			problems = append(problems, "reducible SCC headed at "+header.String()+" has no matching natural loop")
+			continue
+		}
+		if len(nl.Body) != len(scc.Blocks) {
+			problems = append(problems, "natural loop and SCC body sizes differ at header "+header.String())
+			continue
+		}
+		inSCC := make(map[ID]bool, len(scc.Blocks))
+		for _, b := range scc.Blocks {
+			inSCC[b.ID] = true
+		}
+		for _, b := range nl.Body {
+			if !inSCC[b.ID] {
+				problems = append(problems, "natural loop body block "+b.String()+" not in SCC headed at "+header.String())
+				break
+			}
+		}
+	}
+	return problems
+}
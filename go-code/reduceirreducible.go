@@ -0,0 +1,86 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// ReduceIrreducibleStats reports how much cloning ReduceIrreducibleLoops
+// did, broken down by the primary header each clone was made to preserve.
+type ReduceIrreducibleStats struct {
+	ClonedBlocksByHeader map[*Block]int
+	Total                int
+}
+
+// ReduceIrreducibleLoops transforms every irreducible SCC in f into an
+// equivalent reducible region by node splitting: for each SCC with
+// multiple entry targets, the target with the lowest reverse-postorder
+// index becomes the primary header, and the sub-CFG reachable (within the
+// SCC) from every other entry target is cloned via the same
+// tailRegion/duplicateRegion machinery duplicateTailsToReduceIrreducibility
+// uses, with the clone's external entry edge redirected to it. budget
+// caps the total number of blocks cloned across the whole function (0
+// uses maxTailDupBlocks); all cloning stops at the first SCC whose clone
+// would exceed the budget, leaving it and the rest for a later call.
+func (f *Func) ReduceIrreducibleLoops(budget int) ReduceIrreducibleStats {
+	if budget == 0 {
+		budget = maxTailDupBlocks
+	}
+	stats := ReduceIrreducibleStats{ClonedBlocksByHeader: map[*Block]int{}}
+	for {
+		sccs := f.sccs()
+		progress := false
+		exhausted := false
+	sccLoop:
+		for i := range sccs {
+			scc := &sccs[i]
+			if !scc.IsLoop() || scc.IsReducible() {
+				continue
+			}
+			targets := scc.EntryTargets()
+			h := choosePrimaryByRPO(f, targets)
+			inSCC := make(map[ID]bool, len(scc.Blocks))
+			for _, b := range scc.Blocks {
+				inSCC[b.ID] = true
+			}
+			for _, t := range targets {
+				if t == h {
+					continue
+				}
+				region := tailRegion(t, h, inSCC)
+				if stats.Total+len(region) > budget {
+					exhausted = true
+					break sccLoop
+				}
+				duplicateRegion(f, t, region)
+				stats.ClonedBlocksByHeader[h] += len(region)
+				stats.Total += len(region)
+				progress = true
+			}
+		}
+		if exhausted || !progress {
+			break
+		}
+		f.invalidateCFG()
+	}
+	if stats.Total > 0 {
+		f.invalidateCFG()
+	}
+	return stats
+}
+
+// choosePrimaryByRPO picks the entry target with the lowest
+// reverse-postorder index, i.e. the one closest to the function's entry.
+func choosePrimaryByRPO(f *Func, targets []*Block) *Block {
+	po := f.postorder()
+	rpoPos := make(map[ID]int, len(po))
+	for i, b := range po {
+		rpoPos[b.ID] = len(po) - 1 - i
+	}
+	best := targets[0]
+	for _, t := range targets[1:] {
+		if rpoPos[t.ID] < rpoPos[best.ID] {
+			best = t
+		}
+	}
+	return best
+}
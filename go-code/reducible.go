@@ -0,0 +1,94 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// ReducibleOptions controls MakeReducible.
+type ReducibleOptions struct {
+	// MaxClones bounds the total number of blocks MakeReducible will
+	// clone across every SCC, mirroring duplicateTailsToReduceIrreducibility's
+	// maxTailDupBlocks budget but exposed to callers that want a
+	// function-wide transformation rather than liveness's narrower
+	// pre-pass.
+	MaxClones int
+}
+
+// ClonedRegion records one secondary-entry tail that MakeReducible
+// duplicated to eliminate a multi-entry SCC.
+type ClonedRegion struct {
+	Original *Block // the secondary entry target that was cloned
+	Clone    *Block // the fresh block redirected predecessors now target
+	Size     int    // number of blocks in the duplicated region
+}
+
+// ReduceReport summarizes what MakeReducible did.
+type ReduceReport struct {
+	Clones      []ClonedRegion
+	Irreducible []*Block // headers of SCCs still irreducible when the budget ran out
+}
+
+// MakeReducible eliminates multi-entry (irreducible) SCCs in f by node
+// splitting: for every irreducible SCC, one entry target is kept as the
+// sole header and every other entry target's tail (the region reachable
+// from it without first passing back through the header, computed by
+// tailRegion) is cloned and the external edges that used to land on it
+// are redirected to the clone, exactly as duplicateTailsToReduceIrreducibility
+// does for its single liveness-driven pass. MakeReducible instead repeats
+// this across the whole function until computeSCCs reports every SCC
+// reducible or opts.MaxClones blocks have been cloned, whichever comes
+// first.
+//
+// A MaxClones of 0 uses maxTailDupBlocks.
+func (f *Func) MakeReducible(opts ReducibleOptions) ReduceReport {
+	budget := opts.MaxClones
+	if budget == 0 {
+		budget = maxTailDupBlocks
+	}
+	var report ReduceReport
+	total := 0
+	for {
+		sccs := f.sccs()
+		progress := false
+		exhausted := false
+	sccLoop:
+		for i := range sccs {
+			scc := &sccs[i]
+			if !scc.IsLoop() || scc.IsReducible() {
+				continue
+			}
+			targets := scc.EntryTargets()
+			primary := choosePrimaryHeader(scc, targets)
+			inSCC := make(map[ID]bool, len(scc.Blocks))
+			for _, b := range scc.Blocks {
+				inSCC[b.ID] = true
+			}
+			for _, t := range targets {
+				if t == primary {
+					continue
+				}
+				region := tailRegion(t, primary, inSCC)
+				if total+len(region) > budget {
+					report.Irreducible = append(report.Irreducible, primary)
+					exhausted = true
+					continue sccLoop
+				}
+				clone := duplicateRegion(f, t, region)
+				report.Clones = append(report.Clones, ClonedRegion{Original: t, Clone: clone, Size: len(region)})
+				total += len(region)
+				progress = true
+			}
+		}
+		if exhausted {
+			break
+		}
+		if !progress {
+			break
+		}
+		f.invalidateCFG()
+	}
+	if total > 0 {
+		f.invalidateCFG()
+	}
+	return report
+}
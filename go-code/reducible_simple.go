@@ -0,0 +1,89 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// makeReducibleDefaultBudget bounds the total number of blocks MakeReducible
+// will clone, same as maxTailDupBlocks's role for duplicateTailsToReduceIrreducibility.
+const makeReducibleDefaultBudget = maxTailDupBlocks
+
+// sccIrreducible reports whether scc is a loop SCC with more than one
+// external entry target -- the condition MakeReducible keeps eliminating
+// SCCs for until none remain.
+func sccIrreducible(scc *SCC) bool {
+	return scc.IsLoop() && !scc.IsReducible()
+}
+
+// MakeReducible eliminates every irreducible SCC in f by node splitting,
+// reusing the tailRegion/duplicateRegion machinery duplicateTailsToReduceIrreducibility
+// and (*Func).MakeReducible already use. It differs from both in which
+// entry target it keeps as the SCC's permanent header: the one with the
+// fewest external entry edges (via choosePrimaryByFewestExternal), rather
+// than the one with the most. Every other entry target's tail is cloned
+// and redirected, exactly as in the other two passes.
+//
+// It iterates until computeSCCs reports no SCC for which sccIrreducible
+// is true, or makeReducibleDefaultBudget blocks have been cloned, whichever
+// comes first. Running out of budget is reported by returning -1 rather
+// than a partial splits count, so callers can tell "made no more progress"
+// apart from "fully reducible already".
+//
+// Returns the number of regions duplicated.
+func MakeReducible(f *Func) (splits int) {
+	total := 0
+	for {
+		sccs := f.sccs()
+		progress := false
+		for i := range sccs {
+			scc := &sccs[i]
+			if !sccIrreducible(scc) {
+				continue
+			}
+			targets := scc.EntryTargets()
+			primary := choosePrimaryByFewestExternal(scc, targets)
+			inSCC := make(map[ID]bool, len(scc.Blocks))
+			for _, b := range scc.Blocks {
+				inSCC[b.ID] = true
+			}
+			for _, t := range targets {
+				if t == primary {
+					continue
+				}
+				region := tailRegion(t, primary, inSCC)
+				if total+len(region) > makeReducibleDefaultBudget {
+					return -1
+				}
+				duplicateRegion(f, t, region)
+				total += len(region)
+				splits++
+				progress = true
+			}
+		}
+		if !progress {
+			break
+		}
+		f.invalidateCFG()
+	}
+	if splits > 0 {
+		f.invalidateCFG()
+	}
+	return splits
+}
+
+// choosePrimaryByFewestExternal picks the entry target receiving the
+// fewest external entry edges, ties broken by lowest block ID for
+// determinism.
+func choosePrimaryByFewestExternal(scc *SCC, targets []*Block) *Block {
+	counts := make(map[ID]int, len(targets))
+	for _, e := range scc.Entries {
+		counts[e.To.ID]++
+	}
+	best := targets[0]
+	for _, t := range targets[1:] {
+		if counts[t.ID] < counts[best.ID] || (counts[t.ID] == counts[best.ID] && t.ID < best.ID) {
+			best = t
+		}
+	}
+	return best
+}
@@ -17,27 +17,50 @@ type liveInfo struct {
 // Based on empirical analysis of 290,000 functions from the Go toolchain.
 func (s *regAllocState) computeLive() {
 	f := s.f
+	// Split slice/string/interface/complex-typed values into their
+	// scalar components first (see decompose.go), so everything below
+	// only ever has to carry one-word live ranges.
+	decomposeCompound(f)
 	// single block functions do not have variables that are live across branches
 	if len(f.Blocks) == 1 {
 		return
 	}
+	s.loopnest = f.loopnest()
+	if s.loopnest.hasIrreducible {
+		// Try to turn secondary loop entries into duplicated tails so
+		// the cheaper SCC 3-pass path below becomes available. This
+		// changes the CFG, so the loopnest (and the postorder we're
+		// about to take) must be recomputed afterward.
+		if duplicateTailsToReduceIrreducibility(f) > 0 {
+			s.loopnest = f.loopnest()
+		}
+	}
 	po := f.postorder()
 	s.live = make([][]liveInfo, f.NumBlocks())
 	s.desired = make([]desiredState, f.NumBlocks())
-	s.loopnest = f.loopnest()
+
+	s.loopnest.computeUnavoidableCalls()
+
+	// TESTING OVERRIDE: sparse-set/worklist path (see regalloc_wto.go).
+	// Selected by computeLiveModeForTesting rather than by CFG shape, so
+	// it's exercised by its own direct benchmarks/cross-check test, not
+	// by the heuristics below.
+	if computeLiveModeForTesting == computeLiveModeWTO {
+		s.computeLiveWTO()
+		return
+	}
 
 	live := f.newSparseMapPos(f.NumValues())
 	defer f.retSparseMapPos(live)
 	t := f.newSparseMapPos(f.NumValues())
 	defer f.retSparseMapPos(t)
 
-	s.loopnest.computeUnavoidableCalls()
-
 	// FAST PATH: Acyclic CFGs (68% of real-world functions)
 	// No loops = no cycles = single postorder pass suffices.
 	// Skip SCC computation entirely - it's wasted work for the majority case.
 	if len(s.loopnest.loops) == 0 {
 		s.computeLiveAcyclic(po, live, t)
+		s.restrictTupleLiveness()
 		return
 	}
 
@@ -47,12 +70,14 @@ func (s *regAllocState) computeLive() {
 	// The cutoff limit is still to be explored.
 	if s.loopnest.hasIrreducible || (len(po) < 30) {
 		s.computeLiveIterative(po, live, t)
+		s.restrictTupleLiveness()
 		return
 	}
 
 	// LOOP PATH: Reducible CFGs with loops (32% of functions)
 	// Use SCC decomposition with 3-pass convergence (empirical guarantee, no proof).
 	s.computeLiveWithLoops(po, live, t)
+	s.restrictTupleLiveness()
 }
 
 // computeLiveAcyclic handles the common case of acyclic CFGs.
@@ -92,10 +117,14 @@ func (s *regAllocState) computeLiveIterative(po []*Block, live, t *sparseMapPos)
 	}
 
 	// Traditional iterative algorithm: Iterate until no changes occur.
+	// Using the hot-trace order instead of plain postorder means the
+	// backward pass visits a definition's hot consumer right after it,
+	// so hot-path distances tend to stabilize on the first iteration.
+	order := f.traceOrder()
 	for iter := 0; ; iter++ {
 		changed := false
 
-		for _, b := range po {
+		for _, b := range order {
 			if s.processBlock(b, live, t, rematIDs, loopLiveIn) {
 				changed = true
 			}
@@ -149,7 +178,7 @@ func (s *regAllocState) computeLiveWithLoops(po []*Block, live, t *sparseMapPos)
 		// NON-TRIVIAL SCC: Apply 3-pass algorithm with alternating order
 		// Empirical finding: ALL SCCs in our 290k-function dataset converge
 		// in exactly 3 passes with alternating traversal order.
-		exitward, entryward := sccAlternatingOrders(scc)
+		exitward, entryward := sccTraceOrders(scc)
 
 		// Pass 1: postorder (exits → entry direction)
 		for _, b := range exitward {
@@ -190,12 +219,15 @@ func (s *regAllocState) processBlock(
 	// arguments to phi nodes are live at this blocks out
 	for _, e := range b.Succs {
 		succ := e.b
-		delta := branchDistance(b, succ)
+		delta := s.edgeDistance(b, succ)
 		for _, v := range succ.Values {
 			if v.Op != OpPhi {
 				break
 			}
 			arg := v.Args[e.i]
+			if isSpilledArg(arg) {
+				continue
+			}
 			if s.values[arg.ID].needReg && (!live.contains(arg.ID) || delta < live.get(arg.ID)) {
 				live.set(arg.ID, delta, v.Pos)
 				update = true
@@ -243,6 +275,13 @@ func (s *regAllocState) processBlock(
 			rematIDs = rematIDs[:0]
 		}
 		for _, a := range v.Args {
+			if isSpilledArg(a) {
+				// Modeled as already spilled (see argspill.go): each use
+				// reloads it from its home slot, so it never needs to be
+				// threaded through live as a value crossing block
+				// boundaries.
+				continue
+			}
 			if s.values[a.ID].needReg {
 				live.set(a.ID, int32(i), v.Pos)
 			}
@@ -262,7 +301,7 @@ func (s *regAllocState) processBlock(
 	changed := false
 	for _, e := range b.Preds {
 		p := e.b
-		delta := branchDistance(p, b)
+		delta := s.edgeDistance(p, b)
 
 		// Start t off with the previously known live values at the end of p
 		t.clear()
@@ -298,6 +337,14 @@ func (s *regAllocState) propagateLoopLiveness(
 ) {
 	f := s.f
 
+	// Loop-invariant values get a cheap, LICM-style liveness boost below:
+	// instead of waiting for the unknownDistance fill-in pass to assign
+	// them whatever distance their first use happens to produce, they're
+	// given a small distance immediately so the allocator prefers to keep
+	// them resident across the entire loop, which is the point of
+	// hoisting them in the first place.
+	s.loopInvariants = s.computeLoopInvariants()
+
 	// Walk the loopnest from outer to inner, adding
 	// all live-in values from their parent. Instead of
 	// a recursive algorithm, iterate in depth order.
@@ -350,7 +397,15 @@ func (s *regAllocState) propagateLoopLiveness(
 		update := false
 		for _, l := range headerLive {
 			if !loopset.contains(l.ID) {
-				loopset.set(l.ID, unknownDistance, src.NoXPos)
+				dist := int32(unknownDistance)
+				if s.loopInvariants[l.ID] == loop {
+					// Invariant w.r.t. this loop: it's good for the
+					// whole loop body, not just until its next
+					// incidental use, so don't leave it to the
+					// successor-driven fill-in pass below.
+					dist = 0
+				}
+				loopset.set(l.ID, dist, src.NoXPos)
 				update = true
 			}
 		}
@@ -387,8 +442,8 @@ func (s *regAllocState) propagateLoopLiveness(
 					if !live.contains(l.ID) || l.dist == unknownDistance {
 						continue
 					}
-					dist := int32(len(succ.Values)) + l.dist + branchDistance(b, succ)
-					dist += numCalls[succ.ID] * unlikelyDistance
+					dist := int32(len(succ.Values)) + l.dist + s.edgeDistance(b, succ)
+					dist += numCalls[succ.ID] * s.coldCallDistance(succ)
 					val := live.get(l.ID)
 					switch {
 					case val == unknownDistance:
@@ -419,6 +474,11 @@ func (s *regAllocState) propagateLoopLiveness(
 }
 
 // computeDesired computes the desired register information at the end of each block.
+//
+// computeDesired is PGO-agnostic: it reads neither s.live nor
+// s.f.pgoEdgeWeights. When a profile is set, it is the s.live distances,
+// tightened on hot edges by edgeDistance, that reflect it; the desired
+// state computed here does not.
 func (s *regAllocState) computeDesired() {
 	var desired desiredState
 	f := s.f
@@ -458,6 +518,37 @@ func (s *regAllocState) computeDesired() {
 			break
 		}
 	}
+
+	// Hoist desired registers for loop-invariant values from each
+	// loop's header out to its preheader, so the pre-loop code sets up
+	// the register the loop body wants instead of leaving it to
+	// whatever the preheader's own desired state happened to be.
+	if len(s.loopInvariants) > 0 {
+		byLoop := map[*loop]map[ID]bool{}
+		for id, l := range s.loopInvariants {
+			ids := byLoop[l]
+			if ids == nil {
+				ids = map[ID]bool{}
+				byLoop[l] = ids
+			}
+			ids[id] = true
+		}
+		for l, ids := range byLoop {
+			ph := l.preheader(s.loopnest)
+			if ph == nil {
+				continue
+			}
+			var hoist desiredState
+			for _, e := range s.desired[l.header.ID].entries {
+				if ids[e.ID] {
+					hoist.entries = append(hoist.entries, e)
+				}
+			}
+			if len(hoist.entries) > 0 {
+				s.desired[ph.ID].merge(&hoist)
+			}
+		}
+	}
 }
 
 func updateLive(t *sparseMapPos, live []liveInfo) []liveInfo {
@@ -471,6 +562,76 @@ func updateLive(t *sparseMapPos, live []liveInfo) []liveInfo {
 	return live
 }
 
+// pgoBlockEdge identifies a CFG edge for the purposes of looking up a
+// profile-guided execution count. It intentionally does not reuse Edge,
+// since PGO counts are keyed by block pair, not by successor position.
+type pgoBlockEdge struct {
+	from, to *Block
+}
+
+// edgeDistance is like branchDistance, but scales the result using
+// per-edge dynamic execution counts from a PGO profile when one is
+// attached to s.f (s.f.pgoEdgeWeights). Edges with no profile data fall
+// back to the static Likely/Unlikely heuristic in branchDistance.
+//
+// The profile gives us a ratio of how often this edge is taken relative
+// to the hottest edge leaving b; distances are scaled so a cold edge
+// looks like a normal-or-worse branch while a dominant hot edge looks
+// at least as good as likelyDistance.
+func (s *regAllocState) edgeDistance(b, succ *Block) int32 {
+	weights := s.f.pgoEdgeWeights
+	if weights == nil {
+		return branchDistance(b, succ)
+	}
+	total := 0.0
+	hot := 0.0
+	for _, e := range b.Succs {
+		w := weights[pgoBlockEdge{b, e.b}]
+		total += w
+		if w > hot {
+			hot = w
+		}
+	}
+	w, ok := weights[pgoBlockEdge{b, succ}]
+	if !ok || total == 0 {
+		return branchDistance(b, succ)
+	}
+	// Scale linearly between unlikelyDistance (cold edge) and
+	// likelyDistance (the hottest edge out of b).
+	ratio := w / hot
+	d := unlikelyDistance - int32(ratio*float64(unlikelyDistance-likelyDistance))
+	if d < likelyDistance {
+		d = likelyDistance
+	}
+	if d > unlikelyDistance {
+		d = unlikelyDistance
+	}
+	return d
+}
+
+// coldCallDistance returns the per-call distance penalty applied when
+// propagating liveness across the calls counted in numCalls[b.ID]. With no
+// profile it is the flat unlikelyDistance penalty used historically; with a
+// PGO profile attached to s.f, calls in a block that the profile shows is
+// actually hot get a much smaller penalty, and calls in a genuinely cold
+// block keep (or exceed) the flat penalty, so register pressure decisions
+// reflect real dynamic call frequency instead of a uniform guess.
+func (s *regAllocState) coldCallDistance(b *Block) int32 {
+	weights := s.f.pgoBlockWeights
+	if weights == nil {
+		return unlikelyDistance
+	}
+	w, ok := weights[b]
+	if !ok || w <= 0 {
+		return unlikelyDistance
+	}
+	d := int32(float64(unlikelyDistance) * (1 - w*0.5))
+	if d < normalDistance {
+		d = normalDistance
+	}
+	return d
+}
+
 func branchDistance(b *Block, s *Block) int32 {
 	if len(b.Succs) == 2 {
 		if b.Succs[0].b == s && b.Likely == BranchLikely ||
@@ -490,6 +490,67 @@ func buildIrreducibleSimple(tb testing.TB) *Func {
 	return fun.f
 }
 
+// buildIrreducibleForEquivalenceCheck creates an irreducible CFG shaped so
+// that duplicateRegion clones a two-block region (S and X) whose secondary
+// entry S ends up with a real, data-dependent merge at runtime: S is
+// reached once from H (the chosen primary header) and then repeatedly from
+// X, the duplicated region's other member. That gives S's clone a
+// predecessor that's internal to the duplicated region (X) interleaved
+// with ones that are external to it (entry, H) in a different relative
+// order than the original block had them in -- exactly the shape
+// TestDuplicateRegionPreservesSemantics needs to tell a correct
+// predecessor-identity-based phi rewrite apart from a positional one.
+//
+//	entry
+//	/   \
+//	▼     ▼
+//	H     S ◄─┐
+//	│     │▲  │
+//	▼     ▼│  │
+//	└────►S│  │
+//	       X──┘
+//	       │
+//	       ▼
+//	      exit
+//
+// H and S are the two entry targets into the {H,S,X} SCC; X closes the
+// cycle back to H but also branches straight back to S, so S sees three
+// distinct incoming edges (entry, H, and X) with different values on
+// each.
+func buildIrreducibleForEquivalenceCheck(tb testing.TB) *Func {
+	c := testConfig(tb)
+	intType := c.config.Types.Int64
+	boolType := c.config.Types.Bool
+
+	fun := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("zero", OpConst64, intType, 0, nil),
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("limit", OpConst64, intType, 10, nil),
+			Valu("cond_entry", OpConstBool, boolType, 1, nil),
+			If("cond_entry", "H", "S")), // Two entries into the {H,S,X} cycle.
+
+		Bloc("H",
+			Goto("S")), // No values: gaining an extra predecessor later (X's clone) is harmless.
+
+		Bloc("S",
+			Valu("phi_s", OpPhi, intType, 0, nil, "zero", "one", "vx"), // From entry, from H, from X.
+			Valu("vs", OpAdd64, intType, 0, nil, "phi_s", "one"),
+			Valu("cond_s", OpLess64, boolType, 0, nil, "vs", "limit"),
+			If("cond_s", "X", "exit")),
+
+		Bloc("X",
+			Valu("vx", OpAdd64, intType, 0, nil, "vs", "one"),
+			Valu("cond_x", OpConstBool, boolType, 1, nil),
+			If("cond_x", "S", "H")), // Always back to S dynamically; the edge to H keeps the SCC closed.
+
+		Bloc("exit",
+			Exit("vs")))
+
+	return fun.f
+}
+
 // buildIrreducibleDiamond creates an irreducible diamond pattern:
 //
 //	  entry
@@ -1000,6 +1061,33 @@ func buildIrreducibleChain(tb testing.TB, numRegions int) *Func {
 // - Heapify down loop (b18 -> b20 -> b18)
 // - Heapify up loop (b26 -> b28 -> b26)
 // - Multiple conditional branches and early returns
+
+// callResult names one projection callTuple should emit for a call's
+// result: its final SSA name and type.
+type callResult struct {
+	name string
+	typ  *types.Type
+}
+
+// callTuple emits call as an OpStaticCall over mem, followed by one
+// OpSelectN projection per entry in results, in index order -- the same
+// shape div_call/div_call2/div5_call below already use for their own
+// multi-result calls. Every call site that needs more than its mem
+// result (or even just its mem result alone) should go through this
+// instead of modeling extra results as additional, independent
+// OpStaticCall values that happen to share an input mem: those aren't
+// actually sequenced relative to each other or to the real call, so
+// nothing stops a reader (or a pass) from mistaking them for two
+// separate calls.
+func callTuple(call, mem string, results ...callResult) []interface{} {
+	entries := make([]interface{}, 0, len(results)+1)
+	entries = append(entries, Valu(call, OpStaticCall, types.TypeMem, 0, nil, mem))
+	for i, r := range results {
+		entries = append(entries, Valu(r.name, OpSelectN, r.typ, int64(i), nil, call))
+	}
+	return entries
+}
+
 func buildHeapSortCFG(c *Conf) *Func {
 	ptrType := c.config.Types.BytePtr
 	intType := c.config.Types.Int
@@ -1289,21 +1377,103 @@ func buildFloatPrecCFG(c *Conf) *Func {
 	memType := types.TypeMem
 	boolType := c.config.Types.Bool
 
+	b2Entries := []interface{}{
+		Valu("x", OpArg, ptrType, 0, nil),
+		Valu("mem", OpInitMem, memType, 0, nil),
+		Valu("sp", OpSP, ptrType, 0, nil),
+		Valu("sb", OpSB, ptrType, 0, nil),
+		Valu("zero_int", OpConst64, intType, 0, nil),
+		Valu("zero_bool", OpConstBool, boolType, 0, nil),
+		Valu("one", OpConst64, intType, 1, nil),
+		Valu("type_ptr", OpLoad, ptrType, 0, nil, "sb", "mem"),
+	}
+	b2Entries = append(b2Entries, callTuple("pool_call", "mem",
+		callResult{"pool_result", ptrType}, callResult{"mem2", memType})...)
+	b2Entries = append(b2Entries,
+		Valu("cmp_type", OpEq64, boolType, 0, nil, "pool_result", "type_ptr"),
+		If("cmp_type", "b3", "b4"))
+
+	b7Entries := callTuple("new_stack_call", "mem2",
+		callResult{"new_stack", ptrType}, callResult{"mem7", memType})
+	b7Entries = append(b7Entries, Goto("b8"))
+
+	b11Entries := callTuple("unit_call", "mem8",
+		callResult{"unit_ptr", ptrType}, callResult{"mem11", memType})
+	b11Entries = append(b11Entries, Goto("b13"))
+
+	b26Entries := []interface{}{
+		Valu("p2", OpPhi, intType, 0, nil, "p2_init", "p2_computed"),
+	}
+	b26Entries = append(b26Entries, callTuple("q_ptr_init_call", "mem13",
+		callResult{"q_ptr_init", ptrType}, callResult{"mem26", memType})...)
+	b26Entries = append(b26Entries, callTuple("f_ptr_init_call", "mem26",
+		callResult{"f_ptr_init", ptrType}, callResult{"mem26b", memType})...)
+	b26Entries = append(b26Entries, Goto("b29"))
+
+	b38Entries := callTuple("new_tab_small_call", "mem29b",
+		callResult{"new_tab_small", ptrType}, callResult{"mem38", memType})
+	b38Entries = append(b38Entries,
+		Valu("cap_small", OpConst64, intType, 1, nil),
+		Goto("b30"))
+
+	b39Entries := callTuple("grow_call", "mem29b",
+		callResult{"new_tab_large", ptrType}, callResult{"new_cap_large", intType}, callResult{"mem39", memType})
+	b39Entries = append(b39Entries, Goto("b30"))
+
+	b89Entries := callTuple("wb_call", "mem30", callResult{"wb_mem", memType})
+	b89Entries = append(b89Entries, Goto("b88"))
+
+	b88Entries := []interface{}{
+		Valu("mem88", OpPhi, memType, 0, nil, "mem30", "wb_mem"),
+	}
+	b88Entries = append(b88Entries, callTuple("sqr_call", "mem88",
+		callResult{"f_ptr_sqr", ptrType}, callResult{"f_len_sqr", intType}, callResult{"mem88_sqr", memType})...)
+	b88Entries = append(b88Entries, Goto("b29"))
+
+	b58Entries := callTuple("alloc1_call", "mem44",
+		callResult{"alloc1", ptrType}, callResult{"mem58", memType})
+	b58Entries = append(b58Entries,
+		Valu("cap1", OpConst64, intType, 1, nil),
+		Goto("b56"))
+
+	b57Entries := []interface{}{
+		Valu("new_cap", OpAdd64, intType, 0, nil, "t_len", "one"),
+	}
+	b57Entries = append(b57Entries, callTuple("alloc_large_call", "mem44",
+		callResult{"alloc_large", ptrType}, callResult{"mem57", memType})...)
+	b57Entries = append(b57Entries, Goto("b56"))
+
+	b62Entries := callTuple("memmove_call", "mem56", callResult{"memmove_mem", memType})
+	b62Entries = append(b62Entries, Goto("b63"))
+
+	b67Entries := callTuple("cmp_call", "mem65b",
+		callResult{"cmp_result", intType}, callResult{"mem67", memType})
+	b67Entries = append(b67Entries,
+		// Use conditional select or just compute min with comparison
+		Valu("p2_lt_p5", OpLess64U, boolType, 0, nil, "p2", "p5_65"),
+		Valu("min_p", OpCondSelect, intType, 0, nil, "p2_lt_p5", "p2", "p5_65"),
+		Valu("is_exact", OpEq64, boolType, 0, nil, "cmp_result", "zero_int"),
+		Exit("mem67"))
+
+	b78Entries := callTuple("alloc1_p5_call", "mem65b",
+		callResult{"alloc1_p5", ptrType}, callResult{"mem78", memType})
+	b78Entries = append(b78Entries,
+		Valu("cap1_p5", OpConst64, intType, 1, nil),
+		Goto("b76"))
+
+	b77Entries := []interface{}{
+		Valu("new_cap_p5", OpAdd64, intType, 0, nil, "t5_len", "one"),
+	}
+	b77Entries = append(b77Entries, callTuple("alloc_large_p5_call", "mem65b",
+		callResult{"alloc_large_p5", ptrType}, callResult{"mem77", memType})...)
+	b77Entries = append(b77Entries, Goto("b76"))
+
+	b82Entries := callTuple("memmove2_call", "mem76", callResult{"memmove2_mem", memType})
+	b82Entries = append(b82Entries, Goto("b66"))
+
 	fun := c.Fun("b2",
 		// Entry block
-		Bloc("b2",
-			Valu("x", OpArg, ptrType, 0, nil),
-			Valu("mem", OpInitMem, memType, 0, nil),
-			Valu("sp", OpSP, ptrType, 0, nil),
-			Valu("sb", OpSB, ptrType, 0, nil),
-			Valu("zero_int", OpConst64, intType, 0, nil),
-			Valu("zero_bool", OpConstBool, boolType, 0, nil),
-			Valu("one", OpConst64, intType, 1, nil),
-			Valu("type_ptr", OpLoad, ptrType, 0, nil, "sb", "mem"),
-			Valu("pool_result", OpStaticCall, ptrType, 0, nil, "mem"),
-			Valu("mem2", OpStaticCall, memType, 0, nil, "mem"),
-			Valu("cmp_type", OpEq64, boolType, 0, nil, "pool_result", "type_ptr"),
-			If("cmp_type", "b3", "b4")),
+		Bloc("b2", b2Entries...),
 
 		// Pool returned correct type
 		Bloc("b3",
@@ -1325,10 +1495,7 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			Goto("b8")),
 
 		// Need to allocate stack
-		Bloc("b7",
-			Valu("new_stack", OpStaticCall, ptrType, 0, nil, "mem2"),
-			Valu("mem7", OpStaticCall, memType, 0, nil, "mem2"),
-			Goto("b8")),
+		Bloc("b7", b7Entries...),
 
 		// Merge stack allocation
 		Bloc("b8",
@@ -1339,10 +1506,7 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			If("denom_zero", "b11", "b9")),
 
 		// Denominator is zero - create unit denominator
-		Bloc("b11",
-			Valu("unit_ptr", OpStaticCall, ptrType, 0, nil, "mem8"),
-			Valu("mem11", OpStaticCall, memType, 0, nil, "mem8"),
-			Goto("b13")),
+		Bloc("b11", b11Entries...),
 
 		// Denominator is non-zero
 		Bloc("b9",
@@ -1395,13 +1559,7 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			Exit("mem13")),
 
 		// Main computation entry - start tab building
-		Bloc("b26",
-			Valu("p2", OpPhi, intType, 0, nil, "p2_init", "p2_computed"),
-			Valu("q_ptr_init", OpStaticCall, ptrType, 0, nil, "mem13"),
-			Valu("mem26", OpStaticCall, memType, 0, nil, "mem13"),
-			Valu("f_ptr_init", OpStaticCall, ptrType, 0, nil, "mem26"),
-			Valu("mem26b", OpStaticCall, memType, 0, nil, "mem26"),
-			Goto("b29")),
+		Bloc("b26", b26Entries...),
 
 		// Tab building loop header
 		Bloc("b29",
@@ -1409,7 +1567,7 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			Valu("f_len", OpPhi, intType, 0, nil, "one", "f_len_sqr"),
 			Valu("r_len", OpPhi, intType, 0, nil, "zero_int", "r_len_div"),
 			Valu("r_ptr", OpPhi, ptrType, 0, nil, "q_ptr_init", "r_ptr_div"),
-			Valu("mem29", OpPhi, memType, 0, nil, "mem26b", "mem88"),
+			Valu("mem29", OpPhi, memType, 0, nil, "mem26b", "mem88_sqr"),
 			Valu("tab_ptr", OpPhi, ptrType, 0, nil, "q_ptr_init", "tab_ptr_new"),
 			Valu("tab_len", OpPhi, intType, 0, nil, "zero_int", "tab_len_new"),
 			Valu("tab_cap", OpPhi, intType, 0, nil, "zero_int", "tab_cap_new"),
@@ -1446,11 +1604,7 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			If("already_alloc", "b41", "b38")),
 
 		// First allocation
-		Bloc("b38",
-			Valu("new_tab_small", OpStaticCall, ptrType, 0, nil, "mem29b"),
-			Valu("mem38", OpStaticCall, memType, 0, nil, "mem29b"),
-			Valu("cap_small", OpConst64, intType, 1, nil),
-			Goto("b30")),
+		Bloc("b38", b38Entries...),
 
 		// Already allocated
 		Bloc("b41",
@@ -1461,12 +1615,7 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			Goto("b39")),
 
 		// Grow slice
-		Bloc("b39",
-			Valu("grow_call", OpStaticCall, ptrType, 0, nil, "mem29b"),
-			Valu("new_tab_large", OpSelectN, ptrType, 0, nil, "grow_call"),
-			Valu("new_cap_large", OpSelectN, intType, 1, nil, "grow_call"),
-			Valu("mem39", OpStaticCall, memType, 0, nil, "mem29b"),
-			Goto("b30")),
+		Bloc("b39", b39Entries...),
 
 		// Merge tab allocation paths
 		Bloc("b30",
@@ -1482,17 +1631,10 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			Goto("b88")),
 
 		// Write barrier path
-		Bloc("b89",
-			Valu("wb_call", OpStaticCall, memType, 0, nil, "mem30"),
-			Goto("b88")),
+		Bloc("b89", b89Entries...),
 
 		// Square f and loop back
-		Bloc("b88",
-			Valu("mem88", OpPhi, memType, 0, nil, "mem30", "wb_call"),
-			Valu("sqr_call", OpStaticCall, ptrType, 0, nil, "mem88"),
-			Valu("f_ptr_sqr", OpSelectN, ptrType, 0, nil, "sqr_call"),
-			Valu("f_len_sqr", OpSelectN, intType, 1, nil, "sqr_call"),
-			Goto("b29")),
+		Bloc("b88", b88Entries...),
 
 		// i-loop header (descending through tab)
 		Bloc("b40",
@@ -1537,18 +1679,10 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			If("t_len_one", "b58", "b57")),
 
 		// Allocate size 1
-		Bloc("b58",
-			Valu("alloc1", OpStaticCall, ptrType, 0, nil, "mem44"),
-			Valu("mem58", OpStaticCall, memType, 0, nil, "mem44"),
-			Valu("cap1", OpConst64, intType, 1, nil),
-			Goto("b56")),
+		Bloc("b58", b58Entries...),
 
 		// Allocate larger
-		Bloc("b57",
-			Valu("new_cap", OpAdd64, intType, 0, nil, "t_len", "one"),
-			Valu("alloc_large", OpStaticCall, ptrType, 0, nil, "mem44"),
-			Valu("mem57", OpStaticCall, memType, 0, nil, "mem44"),
-			Goto("b56")),
+		Bloc("b57", b57Entries...),
 
 		// Merge allocation
 		Bloc("b56",
@@ -1564,13 +1698,11 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			Goto("b63")),
 
 		// Do memmove
-		Bloc("b62",
-			Valu("memmove_call", OpStaticCall, memType, 0, nil, "mem56"),
-			Goto("b63")),
+		Bloc("b62", b62Entries...),
 
 		// Merge memmove
 		Bloc("b63",
-			Valu("mem63", OpPhi, memType, 0, nil, "mem56", "memmove_call"),
+			Valu("mem63", OpPhi, memType, 0, nil, "mem56", "memmove_mem"),
 			Goto("b42")),
 
 		// i-loop increment (decrement)
@@ -1603,14 +1735,7 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			If("r5_nonzero", "b67", "b68")),
 
 		// r5 != 0: exit p5 loop, return
-		Bloc("b67",
-			Valu("cmp_result", OpStaticCall, intType, 0, nil, "mem65b"),
-			Valu("mem67", OpStaticCall, memType, 0, nil, "mem65b"),
-			// Use conditional select or just compute min with comparison
-			Valu("p2_lt_p5", OpLess64U, boolType, 0, nil, "p2", "p5_65"),
-			Valu("min_p", OpCondSelect, intType, 0, nil, "p2_lt_p5", "p2", "p5_65"),
-			Valu("is_exact", OpEq64, boolType, 0, nil, "cmp_result", "zero_int"),
-			Exit("mem67")),
+		Bloc("b67", b67Entries...),
 
 		// r5 == 0: continue dividing
 		Bloc("b68",
@@ -1627,18 +1752,10 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			If("t5_len_one", "b78", "b77")),
 
 		// Allocate size 1
-		Bloc("b78",
-			Valu("alloc1_p5", OpStaticCall, ptrType, 0, nil, "mem65b"),
-			Valu("mem78", OpStaticCall, memType, 0, nil, "mem65b"),
-			Valu("cap1_p5", OpConst64, intType, 1, nil),
-			Goto("b76")),
+		Bloc("b78", b78Entries...),
 
 		// Allocate larger
-		Bloc("b77",
-			Valu("new_cap_p5", OpAdd64, intType, 0, nil, "t5_len", "one"),
-			Valu("alloc_large_p5", OpStaticCall, ptrType, 0, nil, "mem65b"),
-			Valu("mem77", OpStaticCall, memType, 0, nil, "mem65b"),
-			Goto("b76")),
+		Bloc("b77", b77Entries...),
 
 		// Merge allocation
 		Bloc("b76",
@@ -1654,13 +1771,11 @@ func buildFloatPrecCFG(c *Conf) *Func {
 			Goto("b66")),
 
 		// Do memmove
-		Bloc("b82",
-			Valu("memmove2_call", OpStaticCall, memType, 0, nil, "mem76"),
-			Goto("b66")),
+		Bloc("b82", b82Entries...),
 
 		// p5 loop increment
 		Bloc("b66",
-			Valu("mem66", OpPhi, memType, 0, nil, "mem76", "memmove2_call"),
+			Valu("mem66", OpPhi, memType, 0, nil, "mem76", "memmove2_mem"),
 			Valu("p5_65_inc", OpAdd64, intType, 0, nil, "p5_65", "one"),
 			Valu("z_len_new", OpPhi, intType, 0, nil, "z_len_phi", "z_len_phi"),
 			Valu("z_ptr_new", OpPhi, ptrType, 0, nil, "z_ptr_phi", "z_ptr_phi"),
@@ -1888,6 +2003,351 @@ func BenchmarkComputeLive_FloatPrec(b *testing.B) {
 	benchmarkComputeLive(b, f)
 }
 
+// BenchmarkComputeLive_MapLookupLoop exercises BuildMapLookupLoop
+// (funcbuilder_patterns_test.go): a bounded loop with a found/not-found
+// diamond and two call sites per iteration, the shape the CountingLoop
+// and Diamond builder patterns exist to make cheap to write.
+func BenchmarkComputeLive_MapLookupLoop(b *testing.B) {
+	c := testConfig(b)
+	f, err := BuildMapLookupLoop(c, 64)
+	if err != nil {
+		b.Fatalf("BuildMapLookupLoop: %v", err)
+	}
+	benchmarkComputeLive(b, f)
+}
+
+// =============================================================================
+// ARGUMENT-SPILL BENCHMARKS
+// =============================================================================
+
+// buildArgsLoop builds a function taking numArgs arguments, all passed
+// straight into a single-block loop that uses each of them once per
+// iteration: entry -> header <-> body -> exit, the same shape as
+// buildSimpleLoop. When spilled is false (the realistic-looking but
+// pessimistic case), every argument is an ordinary OpArg and must be
+// live from the entry block through every iteration of the loop. When
+// spilled is true, every argument is marked SpilledArg (see argspill.go)
+// and computeLive never carries it past the block that uses it, which is
+// the comparison this benchmark exists to make.
+func buildArgsLoop(tb testing.TB, numArgs int, spilled bool) *Func {
+	c := testConfig(tb)
+	intType := c.config.Types.Int64
+	boolType := c.config.Types.Bool
+
+	var argAux interface{}
+	if spilled {
+		argAux = SpilledArg
+	}
+
+	entryVals := make([]any, 0, numArgs+4)
+	entryVals = append(entryVals,
+		Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+		Valu("zero", OpConst64, intType, 0, nil),
+		Valu("one", OpConst64, intType, 1, nil),
+		Valu("limit", OpConst64, intType, 100, nil))
+	for j := 0; j < numArgs; j++ {
+		entryVals = append(entryVals, Valu(fmt.Sprintf("arg%d", j), OpArg, intType, 0, argAux))
+	}
+	entryVals = append(entryVals, Goto("header"))
+
+	blocs := []bloc{Bloc("entry", entryVals...)}
+
+	blocs = append(blocs, Bloc("header",
+		Valu("i", OpPhi, intType, 0, nil, "zero", "i_inc"),
+		Valu("cmp", OpLess64, boolType, 0, nil, "i", "limit"),
+		If("cmp", "body", "exit")))
+
+	bodyVals := make([]any, 0, numArgs+2)
+	for j := 0; j < numArgs; j++ {
+		bodyVals = append(bodyVals,
+			Valu(fmt.Sprintf("acc%d", j), OpAdd64, intType, 0, nil, "i", fmt.Sprintf("arg%d", j)))
+	}
+	bodyVals = append(bodyVals,
+		Valu("i_inc", OpAdd64, intType, 0, nil, "i", "one"),
+		Goto("header"))
+	blocs = append(blocs, Bloc("body", bodyVals...))
+
+	blocs = append(blocs, Bloc("exit", Exit("mem")))
+
+	fun := c.Fun("entry", blocs...)
+	return fun.f
+}
+
+func BenchmarkComputeLive_ArgsLoop_Unspilled_20(b *testing.B) {
+	f := buildArgsLoop(b, 20, false)
+	benchmarkComputeLive(b, f)
+}
+
+func BenchmarkComputeLive_ArgsLoop_Spilled_20(b *testing.B) {
+	f := buildArgsLoop(b, 20, true)
+	benchmarkComputeLive(b, f)
+}
+
+// TestComputeLiveSpilledArg checks the distinction buildArgsLoop's two
+// benchmarks are meant to show: an ordinary OpArg used inside a loop
+// stays live out of the loop header (it has to cross the back edge
+// into every iteration), while the same argument marked SpilledArg does
+// not, since each use reloads it instead.
+func TestComputeLiveSpilledArg(t *testing.T) {
+	for _, spilled := range []bool{false, true} {
+		f := buildArgsLoop(t, 1, spilled)
+		s := &regAllocState{}
+		s.init(f)
+		s.computeLive()
+
+		var arg *Value
+		for _, v := range f.Entry.Values {
+			if v.Op == OpArg {
+				arg = v
+			}
+		}
+		if arg == nil {
+			t.Fatalf("spilled=%v: no OpArg value found in entry block", spilled)
+		}
+
+		header := f.Entry.Succs[0].b
+		liveAtHeader := false
+		for _, e := range s.live[header.ID] {
+			if e.ID == arg.ID {
+				liveAtHeader = true
+			}
+		}
+		if want := !spilled; liveAtHeader != want {
+			t.Errorf("spilled=%v: arg live out of loop header = %v, want %v", spilled, liveAtHeader, want)
+		}
+	}
+}
+
+// =============================================================================
+// COMPOUND-TYPE BENCHMARKS
+// =============================================================================
+
+// buildFloatPrecCompositeCFG is the un-decomposed counterpart to
+// buildFloatPrecCFG's own hand-decomposed ptr/len/cap triples: q is a
+// genuine slice-typed value, built with OpSliceMake and carried around
+// the loop as a single slice-typed Phi, instead of three separate scalar
+// values -- so decomposeCompound (see decompose.go) has real work to do
+// before computeLive runs on it.
+func buildFloatPrecCompositeCFG(c *Conf) *Func {
+	ptrType := c.config.Types.BytePtr
+	intType := c.config.Types.Int
+	memType := types.TypeMem
+	boolType := c.config.Types.Bool
+	sliceType := types.NewSlice(c.config.Types.Float64)
+
+	blocs := []bloc{
+		Bloc("entry",
+			Valu("mem", OpInitMem, memType, 0, nil),
+			Valu("x", OpArg, ptrType, 0, nil),
+			Valu("zero", OpConst64, intType, 0, nil),
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("limit", OpConst64, intType, 100, nil),
+			Valu("q_ptr0", OpLoad, ptrType, 0, nil, "x", "mem"),
+			Valu("q_len0", OpConst64, intType, 8, nil),
+			Valu("q_cap0", OpConst64, intType, 8, nil),
+			Valu("q0", OpSliceMake, sliceType, 0, nil, "q_ptr0", "q_len0", "q_cap0"),
+			Goto("header")),
+
+		Bloc("header",
+			Valu("i", OpPhi, intType, 0, nil, "zero", "i_inc"),
+			Valu("q", OpPhi, sliceType, 0, nil, "q0", "q_next"),
+			Valu("cmp", OpLess64, boolType, 0, nil, "i", "limit"),
+			If("cmp", "body", "exit")),
+
+		Bloc("body",
+			Valu("q_len", OpSliceLen, intType, 0, nil, "q"),
+			Valu("q_len_inc", OpAdd64, intType, 0, nil, "q_len", "one"),
+			Valu("q_next", OpSliceMake, sliceType, 0, nil, "q_ptr0", "q_len_inc", "q_cap0"),
+			Valu("i_inc", OpAdd64, intType, 0, nil, "i", "one"),
+			Goto("header")),
+
+		Bloc("exit",
+			Valu("q_ptr_final", OpSlicePtr, ptrType, 0, nil, "q"),
+			Exit("mem")),
+	}
+
+	fun := c.Fun("entry", blocs...)
+	return fun.f
+}
+
+func BenchmarkComputeLive_FloatPrec_Composite(b *testing.B) {
+	c := testConfig(b)
+	f := buildFloatPrecCompositeCFG(c)
+	benchmarkComputeLive(b, f)
+}
+
+// TestDecomposeCompoundSliceLoop checks that decomposeCompound adds three
+// scalar Phis (ptr, len, cap) alongside the slice-typed Phi "q" in
+// buildFloatPrecCompositeCFG's loop header, and that the body's
+// OpSliceLen(q)/exit's OpSlicePtr(q) projections collapse into copies of
+// those decomposed Phis instead of remaining projections of the whole
+// slice. The original slice-typed Phi itself is left in place, unused --
+// same as any other dead value this pass produces, a later deadcode pass
+// reclaims it (see decompose.go's doc comment).
+func TestDecomposeCompoundSliceLoop(t *testing.T) {
+	c := testConfig(t)
+	f := buildFloatPrecCompositeCFG(c)
+	decomposeCompound(f)
+
+	var header *Block
+	for _, b := range f.Blocks {
+		if len(b.Preds) == 2 {
+			header = b
+		}
+	}
+	if header == nil {
+		t.Fatalf("could not find loop header block")
+	}
+
+	scalarPhis := 0
+	for _, v := range header.Values {
+		if v.Op == OpPhi && !v.Type.IsSlice() {
+			scalarPhis++
+		}
+	}
+	// i, plus the slice Phi's three scalar replacements (ptr, len, cap).
+	if scalarPhis != 4 {
+		t.Errorf("header has %d non-slice phis after decompose, want 4 (i + ptr/len/cap)", scalarPhis)
+	}
+
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			if v.Op == OpSliceLen || v.Op == OpSlicePtr {
+				t.Errorf("projection %v should have collapsed to a copy after decompose", v)
+			}
+		}
+	}
+}
+
+// TestDecomposeCompoundOpaqueArgNoSelfCopy checks decomposeCompound against
+// the opaque path decomposeValue takes for a compound value it can't read
+// parts out of directly -- here a slice-typed OpArg, projected by two
+// pre-existing OpSliceLen/OpSlicePtr values -- rather than
+// TestDecomposeCompoundSliceLoop's OpSliceMake/OpPhi path. decomposeValue
+// builds its own internal projection Values for an opaque compound value
+// and records them as its parts; Pass 4 must not also try to collapse
+// those internal projections into copies of themselves.
+func TestDecomposeCompoundOpaqueArgNoSelfCopy(t *testing.T) {
+	c := testConfig(t)
+	memType := types.TypeMem
+	floatType := c.config.Types.Float64
+	sliceType := types.NewSlice(floatType)
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, memType, 0, nil),
+			Valu("s", OpArg, sliceType, 0, nil),
+			Valu("s_len", OpSliceLen, c.config.Types.Int, 0, nil, "s"),
+			Valu("s_ptr", OpSlicePtr, types.NewPtr(floatType), 0, nil, "s"),
+			Exit("mem")))
+
+	f := fut.f
+	decomposeCompound(f)
+
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			for _, a := range v.Args {
+				if a == v {
+					t.Fatalf("value %v has itself as an argument after decompose (self-referential copy)", v)
+				}
+			}
+		}
+	}
+
+	sLen, sPtr := fut.values["s_len"], fut.values["s_ptr"]
+	if sLen.Op != OpCopy {
+		t.Errorf("expected s_len to collapse into a copy of the decomposed len, got %v", sLen.Op)
+	}
+	if sPtr.Op != OpCopy {
+		t.Errorf("expected s_ptr to collapse into a copy of the decomposed ptr, got %v", sPtr.Op)
+	}
+}
+
+// =============================================================================
+// WTO (WEAK TOPOLOGICAL ORDER) BENCHMARKS
+// =============================================================================
+
+// benchmarkComputeLiveWTO is benchmarkComputeLive, but forcing the
+// sparse-set/worklist path (see regalloc_wto.go) via
+// computeLiveModeForTesting instead of letting computeLive's own
+// heuristics pick a path.
+func benchmarkComputeLiveWTO(b *testing.B, build func() *Func) {
+	prev := computeLiveModeForTesting
+	computeLiveModeForTesting = computeLiveModeWTO
+	defer func() { computeLiveModeForTesting = prev }()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		f := build()
+		b.StartTimer()
+		s := &regAllocState{}
+		s.init(f)
+		s.computeLive()
+	}
+}
+
+func BenchmarkComputeLive_WTO_Irreducible_Simple(b *testing.B) {
+	benchmarkComputeLiveWTO(b, func() *Func { return buildIrreducibleSimple(b) })
+}
+
+func BenchmarkComputeLive_WTO_Irreducible_MultiEntry10(b *testing.B) {
+	benchmarkComputeLiveWTO(b, func() *Func { return buildIrreducibleMultiEntry(b, 10) })
+}
+
+func BenchmarkComputeLive_WTO_Irreducible_Nested10(b *testing.B) {
+	benchmarkComputeLiveWTO(b, func() *Func { return buildIrreducibleNested(b, 10) })
+}
+
+func BenchmarkComputeLive_WTO_Nested_10(b *testing.B) {
+	benchmarkComputeLiveWTO(b, func() *Func { return buildNestedLoops(b, 10) })
+}
+
+// TestComputeLiveWTOAgreesWithDefault cross-checks computeLiveWTO against
+// whatever path computeLive's own heuristics would otherwise have
+// picked: both must agree, block for block, on how many values are live
+// at each block's end. f is built fresh for each mode rather than
+// shared, since computeLive mutates its Func in place (decomposeCompound,
+// duplicateTailsToReduceIrreducibility) and running it twice on the same
+// *Func would decompose/duplicate it twice over, not compare the same
+// starting point.
+func TestComputeLiveWTOAgreesWithDefault(t *testing.T) {
+	builders := map[string]func(testing.TB) *Func{
+		"IrreducibleSimple":      func(tb testing.TB) *Func { return buildIrreducibleSimple(tb) },
+		"IrreducibleMultiEntry5": func(tb testing.TB) *Func { return buildIrreducibleMultiEntry(tb, 5) },
+		"IrreducibleNested5":     func(tb testing.TB) *Func { return buildIrreducibleNested(tb, 5) },
+		"NestedLoops5":           func(tb testing.TB) *Func { return buildNestedLoops(tb, 5) },
+		"SimpleLoop3":            func(tb testing.TB) *Func { return buildSimpleLoop(tb, 3) },
+	}
+
+	for name, build := range builders {
+		t.Run(name, func(t *testing.T) {
+			fDefault := build(t)
+			sDefault := &regAllocState{}
+			sDefault.init(fDefault)
+			sDefault.computeLive()
+
+			prev := computeLiveModeForTesting
+			computeLiveModeForTesting = computeLiveModeWTO
+			fWTO := build(t)
+			sWTO := &regAllocState{}
+			sWTO.init(fWTO)
+			sWTO.computeLive()
+			computeLiveModeForTesting = prev
+
+			if len(fDefault.Blocks) != len(fWTO.Blocks) {
+				t.Fatalf("builder %q produced different block counts across calls (%d vs %d), not deterministic enough for this cross-check", name, len(fDefault.Blocks), len(fWTO.Blocks))
+			}
+			for i := range fDefault.Blocks {
+				bd, bw := fDefault.Blocks[i], fWTO.Blocks[i]
+				if gd, gw := len(sDefault.live[bd.ID]), len(sWTO.live[bw.ID]); gd != gw {
+					t.Errorf("block %d: default live-out count = %d, WTO live-out count = %d", i, gd, gw)
+				}
+			}
+		})
+	}
+}
+
 // Core benchmark runner
 func benchmarkComputeLive(b *testing.B, f *Func) {
 	b.ResetTimer()
@@ -1,7 +1,8 @@
 type liveInfo struct {
-	ID   ID       // ID of value
-	dist int32    // # of instructions before next use
-	pos  src.XPos // source position of next use
+	ID        ID       // ID of value
+	dist      int32    // # of instructions before next use
+	pos       src.XPos // source position of next use
+	invariant bool     // value is loop-invariant in the loop live-in'ing it; see invariantInfo
 }
 
 // allLoopsSimple reports whether all loops have nesting depth <= maxDepth.
@@ -41,6 +42,7 @@ func (s *regAllocState) computeLive() {
 	s.live = make([][]liveInfo, f.NumBlocks())
 	s.desired = make([]desiredState, f.NumBlocks())
 	s.loopnest = f.loopnest()
+	s.loopInvariants = computeLoopInvariants(f, s.loopnest)
 
 	s.loopnest.computeUnavoidableCalls()
 
@@ -120,10 +122,14 @@ func (s *regAllocState) computeLiveIterative(po []*Block, live, t *sparseMapPos)
 		defer f.Cache.freeInt32Slice(numCalls)
 	}
 
+	// Iterate in trace order rather than plain postorder: a hot
+	// definition's consumer is visited right after it, so dist tightens
+	// on the hot path and the fixed point tends to land sooner.
+	order := traceOrder(f)
 	for iter := 0; ; iter++ {
 		changed := false
 
-		for _, b := range po {
+		for _, b := range order {
 			if s.processBlock(b, live, t, rematIDs, loopLiveIn, numCalls) {
 				changed = true
 			}
@@ -180,7 +186,7 @@ func (s *regAllocState) computeLiveWithSccs(po []*Block, live, t *sparseMapPos)
 		// NON-TRIVIAL SCC: Apply 2-pass algorithm with alternating order
 		// Empirical finding: Two passes are sufficient for  ALL SCCs in our
 		// 290k-CFGs dataset to be good.
-		entryward, exitward := sccAlternatingOrdersDFS(scc.Blocks)
+		entryward, exitward := sccHotOrders(f, scc.Blocks)
 		// processBlock → populates s.live[].dist (distances to next use)
 		order := entryward
 		iter := 0
@@ -235,7 +241,7 @@ func (s *regAllocState) processBlock(
 	// arguments to phi nodes are live at this blocks out
 	for _, e := range b.Succs {
 		succ := e.b
-		delta := branchDistance(b, succ)
+		delta := s.branchDistance(b, succ)
 		for _, v := range succ.Values {
 			if v.Op != OpPhi {
 				break
@@ -275,10 +281,21 @@ func (s *regAllocState) processBlock(
 				numCalls[b.ID]++
 			}
 			rematIDs = rematIDs[:0]
+			loop := s.loopnest.b2l[b.ID]
 			c := live.contents()
 			for i := range c {
-				c[i].val += unlikelyDistance
 				vid := c[i].key
+				// Loop-invariant values aren't pushed past a
+				// call here: they're candidates for a
+				// callee-saved register across the whole
+				// loop (the point of hoisting them in the
+				// first place), so a call inside the loop
+				// shouldn't make them look like better spill
+				// candidates than values the call doesn't
+				// affect.
+				if !s.loopInvariants.isInvariant(loop, vid) {
+					c[i].val += unlikelyDistance
+				}
 				if s.values[vid].rematerializeable {
 					rematIDs = append(rematIDs, vid)
 				}
@@ -301,7 +318,9 @@ func (s *regAllocState) processBlock(
 	if loopLiveIn != nil {
 		loop := s.loopnest.b2l[b.ID]
 		if loop != nil && loop.header.ID == b.ID {
-			loopLiveIn[loop] = updateLive(live, nil)
+			in := updateLive(live, nil)
+			s.stampInvariants(in, loop)
+			loopLiveIn[loop] = in
 		}
 	}
 
@@ -310,7 +329,7 @@ func (s *regAllocState) processBlock(
 	changed := false
 	for _, e := range b.Preds {
 		p := e.b
-		delta := branchDistance(p, b)
+		delta := s.branchDistance(p, b)
 
 		// Start t off with the previously known live values at the end of p
 		t.clear()
@@ -422,6 +441,7 @@ func (s *regAllocState) propagateLoopLiveness(
 		if update {
 			s.live[b.ID] = updateLive(loopset, s.live[b.ID])
 		}
+		s.stampInvariants(s.live[b.ID], loop)
 	}
 	if f.pass.debug > regDebug {
 		s.debugPrintLive("after loop propagation", f, s.live, s.desired)
@@ -452,7 +472,7 @@ func (s *regAllocState) propagateLoopLiveness(
 					if !live.contains(l.ID) || l.dist == unknownDistance {
 						continue
 					}
-					dist := int32(len(succ.Values)) + l.dist + branchDistance(b, succ)
+					dist := int32(len(succ.Values)) + l.dist + s.branchDistance(b, succ)
 					dist += numCalls[succ.ID] * unlikelyDistance
 					val := live.get(l.ID)
 					switch {
@@ -569,28 +589,136 @@ func updateLive(t *sparseMapPos, live []liveInfo) []liveInfo {
 		live = make([]liveInfo, 0, t.size())
 	}
 	for _, e := range t.contents() {
-		live = append(live, liveInfo{e.key, e.val, e.pos})
+		live = append(live, liveInfo{ID: e.key, dist: e.val, pos: e.pos})
 	}
 	return live
 }
 
-// branchDistance calculates the distance between a block and a
-// successor in pseudo-instructions. This is used to indicate
-// likeliness
-func branchDistance(b *Block, s *Block) int32 {
-	if len(b.Succs) == 2 {
-		if b.Succs[0].b == s && b.Likely == BranchLikely ||
-			b.Succs[1].b == s && b.Likely == BranchUnlikely {
-			return likelyDistance
+// stampInvariants sets the invariant flag on every entry of live that
+// invariantInfo marks as loop invariant for l. It's a best-effort
+// denormalization: entries rebuilt later via updateLive (which only has
+// a sparseMapPos's key/val/pos to work from) start with invariant false
+// again, so callers that need an authoritative answer should prefer
+// s.loopInvariants.isInvariant(l, id) directly. This just saves that
+// lookup for consumers that already have a liveInfo in hand.
+func (s *regAllocState) stampInvariants(live []liveInfo, l *loop) {
+	if s.loopInvariants == nil || l == nil {
+		return
+	}
+	for i := range live {
+		live[i].invariant = s.loopInvariants.isInvariant(l, live[i].ID)
+	}
+}
+
+const (
+	likelyEdgeWeight   float32 = 1.0
+	normalEdgeWeight   float32 = 0.5
+	unlikelyEdgeWeight float32 = 0.1
+)
+
+// edgeWeight returns a weight in (0, 1] for the edge from -> to. With
+// profile data for from's edges (s.f.pgoEdgeWeights, the same raw PGO
+// edge-count table regalloc.go's edgeDistance uses), it is to's count
+// relative to the hottest edge leaving from: 1.0 for the hottest,
+// smaller for colder, unlikelyEdgeWeight for a successor with no count.
+// Without it, a two-way branch gets the static likely/unlikely split
+// keyed off from.Likely, or normalEdgeWeight (0.5) on both edges when
+// Likely is unset, and any other edge (a single-successor block's, for
+// one) gets normalEdgeWeight as well.
+//
+// Per-block rows are computed once and cached on Func
+// (cachedSCCEdgeWeights), since branchDistance below is called once per
+// edge on every processBlock/propagateLoopLiveness pass.
+func (s *regAllocState) edgeWeight(from, to *Block) float32 {
+	rows := s.f.cachedSCCEdgeWeights
+	if rows == nil {
+		rows = make(map[ID]map[ID]float32)
+		s.f.cachedSCCEdgeWeights = rows
+	}
+	row, ok := rows[from.ID]
+	if !ok {
+		row = buildSCCEdgeWeightRow(s.f, from)
+		rows[from.ID] = row
+	}
+	if w, ok := row[to.ID]; ok {
+		return w
+	}
+	return normalEdgeWeight
+}
+
+// buildSCCEdgeWeightRow computes b's outgoing edgeWeight values.
+func buildSCCEdgeWeightRow(f *Func, b *Block) map[ID]float32 {
+	row := make(map[ID]float32, len(b.Succs))
+	if profile := f.pgoEdgeWeights; profile != nil {
+		hot := 0.0
+		haveData := false
+		for _, e := range b.Succs {
+			if w, ok := profile[pgoBlockEdge{b, e.b}]; ok {
+				haveData = true
+				if w > hot {
+					hot = w
+				}
+			}
 		}
-		if b.Succs[0].b == s && b.Likely == BranchUnlikely ||
-			b.Succs[1].b == s && b.Likely == BranchLikely {
-			return unlikelyDistance
+		if haveData && hot > 0 {
+			for _, e := range b.Succs {
+				if w, ok := profile[pgoBlockEdge{b, e.b}]; ok {
+					row[e.b.ID] = float32(w / hot)
+				} else {
+					row[e.b.ID] = unlikelyEdgeWeight
+				}
+			}
+			return row
+		}
+	}
+	if len(b.Succs) == 2 {
+		switch b.Likely {
+		case BranchLikely:
+			row[b.Succs[0].b.ID] = likelyEdgeWeight
+			row[b.Succs[1].b.ID] = unlikelyEdgeWeight
+		case BranchUnlikely:
+			row[b.Succs[0].b.ID] = unlikelyEdgeWeight
+			row[b.Succs[1].b.ID] = likelyEdgeWeight
+		default:
+			row[b.Succs[0].b.ID] = normalEdgeWeight
+			row[b.Succs[1].b.ID] = normalEdgeWeight
+		}
+	}
+	return row
+}
+
+// branchDistance calculates the distance between a block and a successor
+// in pseudo-instructions, scaled by edgeWeight so dynamic hotness (not
+// just the static Likely/Unlikely split) drives the liveness "next-use
+// distance": baseDistance is divided by the edge's weight, so an edge of
+// weight 1.0 leaves it unchanged, a normal-weight (0.5) edge doubles it,
+// and a colder one inflates it further, up to unlikelyDistance, so
+// pickReg spills the cold-path value first.
+func (s *regAllocState) branchDistance(b, succ *Block) int32 {
+	baseDistance := int32(normalDistance)
+	if len(b.Succs) == 2 {
+		if b.Succs[0].b == succ && b.Likely == BranchLikely ||
+			b.Succs[1].b == succ && b.Likely == BranchUnlikely {
+			baseDistance = likelyDistance
+		} else if b.Succs[0].b == succ && b.Likely == BranchUnlikely ||
+			b.Succs[1].b == succ && b.Likely == BranchLikely {
+			baseDistance = unlikelyDistance
 		}
 	}
+	w := s.edgeWeight(b, succ)
+	if w <= 0 {
+		w = unlikelyEdgeWeight
+	}
 	// Note: the branch distance must be at least 1 to distinguish the control
 	// value use from the first user in a successor block.
-	return normalDistance
+	d := int32(float32(baseDistance) / w)
+	if d < likelyDistance {
+		d = likelyDistance
+	}
+	if d > unlikelyDistance {
+		d = unlikelyDistance
+	}
+	return d
 }
 
 func (s *regAllocState) debugPrintLive(stage string, f *Func, live [][]liveInfo, desired []desiredState) {
@@ -0,0 +1,179 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// invalidateCFGBlocks is invalidateCFG's incremental counterpart for a
+// localized edit that only touches the block edges of dirty -- a single
+// block split, a newly broken critical edge, a duplicated tail -- rather
+// than a wholesale CFG rewrite. This snapshot has no incremental
+// dominator- or SCC-maintenance algorithm to patch those structures in
+// place, so the structural caches are dropped exactly as invalidateCFG
+// drops them; the one thing this function does better is
+// cachedSCCEdgeWeights, which is keyed per-block and so can be pruned
+// precisely: only dirty's own rows (their Succs changed) and their
+// predecessors' rows (whose Succs entry for a dirty target may now
+// score differently) are invalidated, leaving every unrelated block's
+// cached row alone.
+//
+// recomputeLiveFor is the liveness-side companion: call it (and let it
+// read the stale, pre-edit SCCs) before calling this function, not
+// after.
+func (f *Func) invalidateCFGBlocks(dirty []*Block) {
+	f.cachedPostorder = nil
+	f.cachedIdom = nil
+	f.cachedSdom = nil
+	f.cachedLoopnest = nil
+	f.cachedSCCs = nil
+	f.cachedTraceOrder = nil
+	f.cachedWTO = nil
+	f.cachedSCCLoopNest = nil
+	f.cachedCondensation = nil
+	f.cachedLoopForest = nil
+	f.cachedSCCTraceOrder = nil
+
+	if f.cachedSCCEdgeWeights == nil {
+		return
+	}
+	for _, b := range dirty {
+		delete(f.cachedSCCEdgeWeights, b.ID)
+		for _, e := range b.Preds {
+			delete(f.cachedSCCEdgeWeights, e.b.ID)
+		}
+	}
+}
+
+// recomputeLiveFor incrementally updates s.live and s.desired after a
+// localized CFG edit that only touched the block edges of the blocks in
+// dirty, instead of re-running computeLive over the whole function. It
+// reports whether it was able to do so; false means it fell back to a
+// full f.invalidateCFG() + s.computeLive(), which is always correct but
+// pays the whole cost again.
+//
+// The incremental path relies on the *pre-edit* SCC decomposition
+// (f.sccs(), read before this function calls f.invalidateCFGBlocks
+// itself) to check that the edit didn't change which SCC any block
+// belongs to: every edge now leaving or
+// entering a dirty block must still respect the SCCs' existing
+// topological order (computeSCCs documents its result as already being
+// in that order). If it does, no SCC merged or split, so postorder,
+// loop nesting, and the acyclic/simple/general fast-path classification
+// computeLive picked are all still valid; only liveness within the
+// owning SCC(s) needs to be redone, via the same
+// processBlock/processBlockDesired fixpoint loop computeLiveWithSccs
+// uses, run just over those SCCs. Every other SCC's s.live/s.desired is
+// left untouched.
+func (s *regAllocState) recomputeLiveFor(dirty []*Block) bool {
+	f := s.f
+	if len(dirty) == 0 {
+		return true
+	}
+	if s.live == nil {
+		// No computeLive has run yet; there's nothing to patch.
+		s.computeLive()
+		return true
+	}
+
+	sccs := f.sccs()
+	blockSCC := make(map[ID]int, f.NumBlocks())
+	for i := range sccs {
+		for _, b := range sccs[i].Blocks {
+			blockSCC[b.ID] = i
+		}
+	}
+
+	if !sccStructureLikelyUnchanged(blockSCC, dirty) {
+		f.invalidateCFG()
+		s.computeLive()
+		return false
+	}
+	f.invalidateCFGBlocks(dirty)
+
+	owning := make(map[int]bool, len(dirty))
+	for _, b := range dirty {
+		owning[blockSCC[b.ID]] = true
+	}
+
+	live := f.newSparseMapPos(f.NumValues())
+	defer f.retSparseMapPos(live)
+	t := f.newSparseMapPos(f.NumValues())
+	defer f.retSparseMapPos(t)
+	rematIDs := make([]ID, 0, 64)
+	var desired desiredState
+
+	// Process the affected SCCs in the same topological (reverse
+	// index) order computeLiveWithSccs does, so any liveness pushed
+	// across an SCC boundary into an earlier, untouched SCC's
+	// predecessor is harmless: that SCC was already solved under the
+	// assumption its successors' liveness couldn't change, which
+	// remains true for every successor SCC we didn't just touch.
+	for idx := len(sccs) - 1; idx >= 0; idx-- {
+		if !owning[idx] {
+			continue
+		}
+		scc := &sccs[idx]
+		if len(scc.Blocks) == 1 {
+			b := scc.Blocks[0]
+			s.processBlock(b, live, t, rematIDs, nil, nil)
+			s.processBlockDesired(b, &desired)
+			continue
+		}
+		entryward, exitward := sccHotOrders(f, scc.Blocks)
+		order := entryward
+		for iter := 0; iter < 3; iter++ {
+			if iter&1 == 0 {
+				order = entryward
+			} else {
+				order = exitward
+			}
+			if !s.processBlocksWithOrder(order, live, t, rematIDs) {
+				break
+			}
+		}
+		for iter := 0; iter < 3; iter++ {
+			if iter&1 == 0 {
+				order = entryward
+			} else {
+				order = exitward
+			}
+			if !s.processDesiredWithOrder(order, &desired) {
+				break
+			}
+		}
+	}
+	return true
+}
+
+// sccStructureLikelyUnchanged conservatively reports whether the CFG
+// edges touching dirty still respect the pre-edit SCCs' topological
+// order: every block in dirty must already have been a member of some
+// SCC (no brand-new blocks), and every edge now leaving or entering it
+// must go to/from the same SCC or strictly forward/backward across the
+// topological order accordingly. A violation (a successor landing in an
+// earlier-or-equal-but-different SCC, or a predecessor landing in a
+// later one) means the edit could have merged two previously distinct
+// SCCs into a new cycle, which this function can't rule out cheaply --
+// callers must treat that as "structure may have changed" and fall back
+// to a full recompute.
+func sccStructureLikelyUnchanged(blockSCC map[ID]int, dirty []*Block) bool {
+	for _, b := range dirty {
+		bi, ok := blockSCC[b.ID]
+		if !ok {
+			return false
+		}
+		for _, e := range b.Succs {
+			si, ok := blockSCC[e.b.ID]
+			if !ok || (si != bi && si <= bi) {
+				return false
+			}
+		}
+		for _, e := range b.Preds {
+			pi, ok := blockSCC[e.b.ID]
+			if !ok || (pi != bi && pi >= bi) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+// TestRecomputeLiveForMatchesFullRecompute builds a small one-loop
+// function, runs computeLive fully to get a baseline, then asks
+// recomputeLiveFor to patch up liveness for the loop header and body
+// blocks as if a pass had just touched their edges without actually
+// changing SCC membership. It checks that the incremental path is taken
+// (the edges are unchanged, so sccStructureLikelyUnchanged must hold)
+// and that it reproduces the same live sets as the full computeLive it
+// was seeded from.
+func TestRecomputeLiveForMatchesFullRecompute(t *testing.T) {
+	c := testConfig(t)
+	intType := c.config.Types.Int64
+	boolType := c.config.Types.Bool
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("sum0", OpConst64, intType, 0, nil),
+			Goto("loop")),
+
+		Bloc("loop",
+			Valu("sum", OpPhi, intType, 0, nil, "sum0", "sum1"),
+			Valu("cond", OpConstBool, boolType, 1, nil),
+			If("cond", "body", "exit")),
+
+		Bloc("body",
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("sum1", OpAdd64, intType, 0, nil, "sum", "one"),
+			Goto("loop")),
+
+		Bloc("exit",
+			Exit("mem")))
+
+	f := fut.f
+	loop, body := fut.blocks["loop"], fut.blocks["body"]
+
+	full := &regAllocState{}
+	full.init(f)
+	full.computeLive()
+
+	incr := &regAllocState{}
+	incr.init(f)
+	incr.computeLive()
+
+	if ok := incr.recomputeLiveFor([]*Block{loop, body}); !ok {
+		t.Fatalf("recomputeLiveFor reported a fallback to full recompute for an edit that didn't change the CFG")
+	}
+
+	for _, b := range f.Blocks {
+		want, got := full.live[b.ID], incr.live[b.ID]
+		if len(want) != len(got) {
+			t.Errorf("block %s: full computeLive found %d live values, recomputeLiveFor found %d", b, len(want), len(got))
+			continue
+		}
+		byID := make(map[ID]liveInfo, len(want))
+		for _, e := range want {
+			byID[e.ID] = e
+		}
+		for _, g := range got {
+			w, ok := byID[g.ID]
+			if !ok {
+				t.Errorf("block %s: recomputeLiveFor reports v%d live, full computeLive doesn't", b, g.ID)
+				continue
+			}
+			if w.dist != g.dist {
+				t.Errorf("block %s: v%d distance mismatch: full=%d incremental=%d", b, g.ID, w.dist, g.dist)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// traceOrder builds a Pettis-Hansen style forward-sequence ordering of
+// f's blocks: reverse postorder picks the next unvisited seed, each trace
+// is extended forward by following the most-likely successor edge
+// (Block.Likely, scored the same way branchDistance does) and backward
+// through predecessors the same way, and a trace ends when no extension
+// qualifies. Concatenating the traces puts hot fall-through paths
+// contiguously, which is what computeLiveIterative and
+// computeLiveWithSccs in regalloc_scc.go use it for: the consumer block of a hot
+// definition is visited right after it, tightening dist on the hot path
+// and helping the SCC 3-pass loop converge sooner.
+//
+// Cached on f, invalidated by invalidateCFG. Neither this function nor
+// its callers offer a switch back to plain postorder; it always builds
+// the trace order.
+func traceOrder(f *Func) []*Block {
+	if f.cachedSCCTraceOrder != nil {
+		return f.cachedSCCTraceOrder
+	}
+	po := f.postorder()
+	rpo := make([]*Block, len(po))
+	rpoPos := make(map[ID]int, len(po))
+	for i, b := range po {
+		rpo[len(po)-1-i] = b
+		rpoPos[b.ID] = len(po) - 1 - i
+	}
+
+	placed := make(map[ID]bool, len(po))
+	order := make([]*Block, 0, len(po))
+
+	extendForward := func(seed *Block) []*Block {
+		var fwd []*Block
+		b := seed
+		for {
+			var best *Block
+			bestScore := -1
+			for i, e := range b.Succs {
+				s := e.b
+				if placed[s.ID] || s == seed {
+					continue
+				}
+				alreadyInTrace := false
+				for _, x := range fwd {
+					if x == s {
+						alreadyInTrace = true
+						break
+					}
+				}
+				if alreadyInTrace {
+					continue
+				}
+				score := branchScore(b, s, i)
+				if score > bestScore {
+					bestScore, best = score, s
+				}
+			}
+			if best == nil {
+				return fwd
+			}
+			fwd = append(fwd, best)
+			b = best
+		}
+	}
+
+	extendBackward := func(seed *Block) []*Block {
+		var bwd []*Block
+		b := seed
+		for {
+			var best *Block
+			bestScore := -1
+			for _, e := range b.Preds {
+				p := e.b
+				if placed[p.ID] || p == seed {
+					continue
+				}
+				alreadyInTrace := false
+				for _, x := range bwd {
+					if x == p {
+						alreadyInTrace = true
+						break
+					}
+				}
+				if alreadyInTrace {
+					continue
+				}
+				for i, se := range p.Succs {
+					if se.b != b {
+						continue
+					}
+					score := branchScore(p, b, i)
+					if score > bestScore {
+						bestScore, best = score, p
+					}
+				}
+			}
+			if best == nil {
+				return bwd
+			}
+			bwd = append(bwd, best)
+			b = best
+		}
+	}
+
+	for _, seed := range rpo {
+		if placed[seed.ID] {
+			continue
+		}
+		bwd := extendBackward(seed)
+		fwd := extendForward(seed)
+		trace := make([]*Block, 0, len(bwd)+1+len(fwd))
+		for i := len(bwd) - 1; i >= 0; i-- {
+			trace = append(trace, bwd[i])
+		}
+		trace = append(trace, seed)
+		trace = append(trace, fwd...)
+		for _, b := range trace {
+			placed[b.ID] = true
+		}
+		order = append(order, trace...)
+	}
+
+	f.cachedSCCTraceOrder = order
+	return order
+}
+
+// branchScore ranks b's i'th successor edge to s by static likeliness,
+// the same three-way classification branchDistance uses.
+func branchScore(b, s *Block, i int) int {
+	if len(b.Succs) == 2 {
+		if i == 0 && b.Likely == BranchLikely || i == 1 && b.Likely == BranchUnlikely {
+			return 2
+		}
+		if i == 0 && b.Likely == BranchUnlikely || i == 1 && b.Likely == BranchLikely {
+			return 0
+		}
+	}
+	return 1
+}
+
+// sccHotOrders is like sccAlternatingOrdersDFS but, for SCCs with more
+// than 3 blocks, seeds the DFS from the block traceOrder would visit
+// first within the SCC, so the hot in-loop path is more likely to
+// stabilize within the first of the three alternating passes.
+func sccHotOrders(f *Func, scc []*Block) (entryward, exitward []*Block) {
+	if len(scc) <= 3 {
+		return sccAlternatingOrdersDFS(scc)
+	}
+	pos := make(map[ID]int, len(scc))
+	for i, b := range traceOrder(f) {
+		pos[b.ID] = i
+	}
+	inSCC := make(map[ID]bool, len(scc))
+	for _, b := range scc {
+		inSCC[b.ID] = true
+	}
+	best := scc[0]
+	for _, b := range scc[1:] {
+		if pos[b.ID] < pos[best.ID] {
+			best = b
+		}
+	}
+	reordered := make([]*Block, len(scc))
+	copy(reordered, scc)
+	for i, b := range reordered {
+		if b == best {
+			reordered[0], reordered[i] = reordered[i], reordered[0]
+			break
+		}
+	}
+	return sccAlternatingOrdersDFS(reordered)
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+// TestPGOBranchDistanceBiasesSpillCandidate synthesizes a hot/cold diamond:
+// entry branches to "hot" and "cold", each carrying one value from entry
+// to an identically-positioned use (same filler count, same use index), so
+// the two paths are symmetric in every way except which edge a PGO
+// profile marks dominant. It verifies that once a profile is attached,
+// computeLive's distance for the value living down the hot edge drops
+// well below the one for the cold edge -- the exact signal pickReg uses
+// to choose a spill candidate, so the cold-path value is the one that
+// gets evicted under register pressure while the hot-path value stays.
+func TestPGOBranchDistanceBiasesSpillCandidate(t *testing.T) {
+	c := testConfig(t)
+	intType := c.config.Types.Int64
+	boolType := c.config.Types.Bool
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("v_hot", OpConst64, intType, 10, nil),
+			Valu("v_cold", OpConst64, intType, 20, nil),
+			Valu("cond", OpConstBool, boolType, 1, nil),
+			If("cond", "hot", "cold")),
+
+		Bloc("hot",
+			Valu("h1", OpAdd64, intType, 0, nil, "one", "one"),
+			Valu("h2", OpAdd64, intType, 0, nil, "h1", "one"),
+			Valu("use_hot", OpAdd64, intType, 0, nil, "v_hot", "h2"),
+			Goto("merge")),
+
+		Bloc("cold",
+			Valu("c1", OpAdd64, intType, 0, nil, "one", "one"),
+			Valu("c2", OpAdd64, intType, 0, nil, "c1", "one"),
+			Valu("use_cold", OpAdd64, intType, 0, nil, "v_cold", "c2"),
+			Goto("merge")),
+
+		Bloc("merge",
+			Exit("mem")))
+
+	f := fut.f
+	entry, hot, cold := fut.blocks["entry"], fut.blocks["hot"], fut.blocks["cold"]
+	vHot, vCold := fut.values["v_hot"], fut.values["v_cold"]
+
+	distances := func() (hotDist, coldDist int32) {
+		s := &regAllocState{}
+		s.init(f)
+		s.computeLive()
+		for _, e := range s.live[entry.ID] {
+			switch e.ID {
+			case vHot.ID:
+				hotDist = e.dist
+			case vCold.ID:
+				coldDist = e.dist
+			}
+		}
+		return
+	}
+
+	baseHot, baseCold := distances()
+	if baseHot != baseCold {
+		t.Fatalf("symmetric diamond with no PGO profile: expected equal distances, got hot=%d cold=%d", baseHot, baseCold)
+	}
+
+	f.pgoEdgeWeights = map[pgoBlockEdge]float64{
+		{entry, hot}:  0.9,
+		{entry, cold}: 0.1,
+	}
+	f.invalidateCFG()
+
+	pgoHot, pgoCold := distances()
+	if pgoHot >= baseHot {
+		t.Errorf("hot edge dominant in profile: expected distance to shrink below %d, got %d", baseHot, pgoHot)
+	}
+	if pgoCold <= baseCold {
+		t.Errorf("cold edge rare in profile: expected distance to grow above %d, got %d", baseCold, pgoCold)
+	}
+	if pgoHot >= pgoCold {
+		t.Errorf("hot-path value should have a shorter next-use distance than the cold-path value so pickReg spills cold first: hot=%d cold=%d", pgoHot, pgoCold)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// computeLiveMode selects which of computeLive's internal
+// implementations runs. This exists alongside regAllocState rather than
+// as one of its fields: regAllocState's own struct (and its init method)
+// aren't declared anywhere in this snapshot, so there's nowhere to add a
+// literal s.mode field.
+// computeLiveModeForTesting is read once, at the top of computeLive; it
+// exists for the WTO/default cross-check and BenchmarkComputeLive_WTO_*
+// below, not for production tuning.
+type computeLiveMode int8
+
+const (
+	computeLiveModeAuto computeLiveMode = iota
+	computeLiveModeWTO
+)
+
+var computeLiveModeForTesting = computeLiveModeAuto
+
+// blockSparseSet is a Briggs/Torczon sparse set over block IDs: push,
+// pop, and contains are all O(1), and clearing is O(1) regardless of how
+// many blocks were ever pushed, since dense is just truncated rather
+// than zeroed.
+type blockSparseSet struct {
+	dense  []*Block
+	sparse []int32
+}
+
+func newBlockSparseSet(n int) *blockSparseSet {
+	sparse := make([]int32, n)
+	for i := range sparse {
+		sparse[i] = -1
+	}
+	return &blockSparseSet{sparse: sparse}
+}
+
+func (s *blockSparseSet) contains(b *Block) bool {
+	i := s.sparse[b.ID]
+	return i >= 0 && int(i) < len(s.dense) && s.dense[i] == b
+}
+
+// push adds b if it isn't already present; a no-op otherwise, so a
+// block already queued for reprocessing isn't queued twice.
+func (s *blockSparseSet) push(b *Block) {
+	if s.contains(b) {
+		return
+	}
+	s.sparse[b.ID] = int32(len(s.dense))
+	s.dense = append(s.dense, b)
+}
+
+// pop removes and returns the most recently pushed block. Popping from
+// the dense slice's tail (rather than its head) keeps every remaining
+// entry's sparse index correct without renumbering.
+func (s *blockSparseSet) pop() *Block {
+	n := len(s.dense) - 1
+	b := s.dense[n]
+	s.dense = s.dense[:n]
+	s.sparse[b.ID] = -1
+	return b
+}
+
+func (s *blockSparseSet) empty() bool { return len(s.dense) == 0 }
+
+// buildWTO orders f's reachable blocks with the single DFS of
+// Bourdoncle's algorithm: on closing a strongly connected component, the
+// component's head is emitted and the rest of the component is then
+// recursively re-partitioned (as if the head's back edges into it were
+// cut), so nested loops come out contiguous and header-first within
+// their enclosing loop. Apart from a loop's head leading its own body,
+// components are appended in the order the DFS closes them, so unlike
+// Bourdoncle's weak topological order a component comes before the
+// components that reach it, and f.Entry's component comes last.
+// Unreachable blocks are omitted, same as f.postorder().
+func buildWTO(f *Func) []*Block {
+	w := &wtoBuilder{
+		num:   make([]int32, f.NumBlocks()),
+		order: make([]*Block, 0, f.NumBlocks()),
+	}
+	w.visit(f.Entry)
+	return w.order
+}
+
+const wtoInf = int32(1) << 30
+
+type wtoBuilder struct {
+	num   []int32 // DFN per block ID; 0 = unvisited, wtoInf = finalized
+	stack []*Block
+	dfn   int32
+	order []*Block
+}
+
+// visit is Bourdoncle's VISIT: it returns the lowest DFN reachable from
+// v (v's own DFN if v isn't part of any not-yet-closed component), and,
+// whenever that minimum equals v's own DFN, closes the component headed
+// by v.
+func (w *wtoBuilder) visit(v *Block) int32 {
+	w.stack = append(w.stack, v)
+	w.dfn++
+	w.num[v.ID] = w.dfn
+	head := w.dfn
+
+	for _, e := range v.Succs {
+		s := e.b
+		var d int32
+		if w.num[s.ID] == 0 {
+			d = w.visit(s)
+		} else {
+			d = w.num[s.ID]
+		}
+		if d < head {
+			head = d
+		}
+	}
+
+	if head == w.num[v.ID] {
+		w.num[v.ID] = wtoInf
+		elem := w.stackPop()
+		loop := elem != v
+		for elem != v {
+			w.num[elem.ID] = 0
+			elem = w.stackPop()
+		}
+		w.component(v, loop)
+	}
+	return head
+}
+
+// component emits v (the head just closed by visit) and, if v heads a
+// real loop, recursively visits whichever of v's successors are still
+// unvisited -- exactly the rest of the SCC v headed, since those are the
+// blocks visit just reset to 0 above -- so they're re-partitioned as a
+// nested WTO rather than folded flat into v's enclosing scope.
+func (w *wtoBuilder) component(v *Block, loop bool) {
+	w.order = append(w.order, v)
+	if !loop {
+		return
+	}
+	for _, e := range v.Succs {
+		s := e.b
+		if w.num[s.ID] == 0 {
+			w.visit(s)
+		}
+	}
+}
+
+func (w *wtoBuilder) stackPop() *Block {
+	n := len(w.stack) - 1
+	b := w.stack[n]
+	w.stack = w.stack[:n]
+	return b
+}
+
+// computeLiveWTO is the sparse-set/worklist computeLive alternative:
+// processBlock (the same per-block scan computeLiveAcyclic and
+// computeLiveIterative already use) is driven by a worklist seeded in
+// WTO order instead of a fixed postorder pass repeated to a dense
+// changed/unchanged fixpoint. On irreducible CFGs and CFGs with deep
+// loop nesting, this means blocks outside the part of the graph still
+// changing are never rescanned, where the iterative path rescans every
+// block on every pass until nothing anywhere changes.
+func (s *regAllocState) computeLiveWTO() {
+	f := s.f
+	order := buildWTO(f)
+
+	rematIDs := make([]ID, 0, 64)
+	live := f.newSparseMapPos(f.NumValues())
+	defer f.retSparseMapPos(live)
+	t := f.newSparseMapPos(f.NumValues())
+	defer f.retSparseMapPos(t)
+
+	worklist := newBlockSparseSet(f.NumBlocks())
+	// Push in reverse WTO order, so the stack-based worklist pops in
+	// forward WTO order on this first pass.
+	for i := len(order) - 1; i >= 0; i-- {
+		worklist.push(order[i])
+	}
+
+	for !worklist.empty() {
+		b := worklist.pop()
+		if s.processBlock(b, live, t, rematIDs, nil) {
+			for _, e := range b.Preds {
+				worklist.push(e.b)
+			}
+		}
+	}
+
+	if f.pass.debug > regDebug {
+		s.debugPrintLive("after WTO worklist", f, s.live, s.desired)
+	}
+	s.restrictTupleLiveness()
+	s.computeDesired()
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// RescheduleCheckHook, when non-nil, is invoked once per backedge split
+// by InsertLoopReschedChecks to populate the new check block: it should
+// append whatever values the embedder's runtime needs (a preemption-flag
+// load, a call, ...) and leave the block ending in a two-way branch, with
+// Succs[0] the fast (continue looping) path and Succs[1] the slow
+// (yield) path. InsertLoopReschedChecks does not hardcode a runtime call
+// op itself; embedders that don't set this hook get a plain passthrough
+// block at the split point and can wire their own check in afterward.
+var RescheduleCheckHook func(f *Func, check *Block)
+
+// InsertLoopReschedChecks inserts a cooperative-scheduling preemption
+// check on every backedge of every loop found by computeSCCs, so it
+// handles irreducible loops correctly (unlike a dominator-based backedge
+// definition, which has no notion of "backedge" for a multi-entry SCC).
+//
+// A backedge is any intra-SCC edge whose target is one of the SCC's
+// entry targets (EntryTargets) — for a reducible loop that's exactly the
+// classic "edge to the header"; for an irreducible one, every entry
+// target is treated as a header, so a jump back to any of them counts.
+// Each such edge is split with a new block, and RescheduleCheckHook (if
+// set) is given the chance to fill it in with an actual check.
+//
+// Returns the backedges that were split, named by their pre-split
+// (from, to) endpoints.
+func InsertLoopReschedChecks(f *Func) []Edge {
+	var split []Edge
+	sccs := f.sccs()
+	for i := range sccs {
+		scc := &sccs[i]
+		if !scc.IsLoop() {
+			continue
+		}
+		headers := scc.EntryTargets()
+		isHeader := make(map[ID]bool, len(headers))
+		for _, h := range headers {
+			isHeader[h.ID] = true
+		}
+		for _, b := range scc.Blocks {
+			for _, e := range append([]Edge(nil), b.Succs...) {
+				if !isHeader[e.b.ID] {
+					continue
+				}
+				split = append(split, e)
+				splitReschedEdge(f, b, e)
+			}
+		}
+	}
+	if len(split) > 0 {
+		f.invalidateCFG()
+	}
+	return split
+}
+
+// splitReschedEdge replaces the edge from b to header (header = e.b) with
+// b -> check -> header, where check is a fresh block RescheduleCheckHook
+// gets to populate. header's Phis keep referring to the same predecessor
+// slot, now occupied by check instead of b, so no operand shuffling is
+// needed as long as AddEdgeTo preserves relative predecessor order — the
+// same assumption duplicateRegion in taildup.go already relies on.
+func splitReschedEdge(f *Func, b *Block, e Edge) {
+	header := e.b
+	check := f.NewBlock(BlockPlain)
+	check.Pos = header.Pos
+
+	b.removeSucc(int(e.i))
+	b.AddEdgeTo(check)
+	check.AddEdgeTo(header)
+
+	if RescheduleCheckHook != nil {
+		RescheduleCheckHook(f, check)
+	}
+}
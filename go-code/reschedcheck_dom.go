@@ -0,0 +1,84 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// insertReschedChecksDom gates InsertLoopReschedChecksDom, the same way
+// useCooperDominators (dom.go) and useHavlakLoopFinder
+// (loopnest_havlak.go) gate their own algorithm choices: this snapshot
+// has nowhere to hang a real "GOEXPERIMENT=preemptibleloops" or
+// "-d=ssa/insert_resched_checks/on" flag, so a package variable stands
+// in for it. A real build would wire this to that flag instead of
+// flipping it directly in tests.
+var insertReschedChecksDom = false
+
+// RescheduleDomCheckHook, when non-nil, is invoked once per backedge
+// split by InsertLoopReschedChecksDom to populate the new check block: it
+// should append whatever values the embedder's runtime needs (a
+// preemption-counter decrement, a guarded call to goschedguarded, ...)
+// and leave the block ending in a branch, with Succs[0] the fast
+// (continue looping) path and Succs[1] the slow (yield) path. A nil hook
+// leaves check a single-successor passthrough, so the splice itself can
+// be exercised without a concrete runtime op.
+var RescheduleDomCheckHook func(f *Func, check *Block)
+
+// InsertLoopReschedChecksDom is InsertLoopReschedChecks' dominator-based
+// sibling: where reschedcheck.go and reschedcheck_reducible.go define a
+// backedge as an SCC-internal edge into one of the SCC's entry targets,
+// this one defines a backedge as any edge b->h where h dominates b --
+// the textbook definition, and one that (unlike the SCC-based one) needs
+// no irreducible-SCC salvage logic of its own to make sense. It still
+// consults f.sccs() for exactly one thing: an SCC that isn't reducible
+// is left alone entirely, since a
+// dominance-true backedge inside an irreducible region doesn't correspond
+// to a single natural loop the way it does in a reducible one.
+//
+// Does nothing unless insertReschedChecksDom is set.
+//
+// Each split backedge keeps the header's existing phis valid without
+// adding any new ones: check occupies the exact predecessor slot b used
+// to occupy (AddEdgeTo/removeSucc preserve predecessor order, the same
+// assumption splitReschedEdge and spliceReschedCheck already rely on),
+// so every phi's operand for that slot is unchanged -- it now just flows
+// through check on its way to header.
+//
+// Returns the backedges that were split, named by their pre-split
+// (from, to) endpoints.
+func InsertLoopReschedChecksDom(f *Func) []Edge {
+	if !insertReschedChecksDom {
+		return nil
+	}
+
+	sdom := f.Sdom()
+	sccs := f.sccs()
+
+	var split []Edge
+	for i := range sccs {
+		scc := &sccs[i]
+		if scc.IsLoop() && !scc.IsReducible() {
+			continue // irreducible region: leave it to ReduceIrreducibleLoops/MakeReducible first
+		}
+		for _, b := range scc.Blocks {
+			for _, e := range append([]Edge(nil), b.Succs...) {
+				h := e.b
+				if !sdom.IsAncestorEq(h, b) {
+					continue
+				}
+				split = append(split, e)
+				check := f.NewBlock(BlockPlain)
+				check.Pos = h.Pos
+				b.removeSucc(int(e.i))
+				b.AddEdgeTo(check)
+				check.AddEdgeTo(h)
+				if RescheduleDomCheckHook != nil {
+					RescheduleDomCheckHook(f, check)
+				}
+			}
+		}
+	}
+	if len(split) > 0 {
+		f.invalidateCFG()
+	}
+	return split
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+// TestInsertLoopReschedChecksDomTerminatesTightLoop builds a single-block
+// self loop (entry -> spin -> spin) modeling an otherwise-infinite tight
+// loop, wires RescheduleDomCheckHook to turn the spliced check block into
+// a counter-driven branch to exit, and checks that the check block --
+// not spin itself -- now owns the only path out of the loop.
+func TestInsertLoopReschedChecksDomTerminatesTightLoop(t *testing.T) {
+	c := testConfig(t)
+	boolType := c.config.Types.Bool
+	memType := types.TypeMem
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, memType, 0, nil),
+			Valu("cond", OpConstBool, boolType, 0, nil),
+			If("cond", "spin", "exit")),
+
+		Bloc("spin",
+			Goto("spin")),
+
+		Bloc("exit",
+			Exit("mem")))
+
+	f := fut.f
+	entry, spin, exit := fut.blocks["entry"], fut.blocks["spin"], fut.blocks["exit"]
+
+	// RescheduleDomCheckHook models a counter-driven preemption check: it
+	// turns check (already wired by the splice with Succs[0] = spin, the
+	// fast path) into a real two-way branch by adding a second edge to
+	// exit (the slow path) and giving it a condition, the same "pass
+	// wires the fast edge, the hook adds the slow one and the control"
+	// split reschedcheck.go's own hook doc describes.
+	var checksRun int
+	insertReschedChecksDom = true
+	RescheduleDomCheckHook = func(f *Func, check *Block) {
+		checksRun++
+		check.Kind = BlockIf
+		check.Control = check.NewValue0(check.Pos, OpConstBool, c.config.Types.Bool, 0)
+		check.AddEdgeTo(exit) // slow path: Succs[1]
+	}
+	defer func() { insertReschedChecksDom = false; RescheduleDomCheckHook = nil }()
+
+	split := InsertLoopReschedChecksDom(f)
+	if len(split) != 1 {
+		t.Fatalf("expected exactly one backedge split, got %d", len(split))
+	}
+	if split[0].b != spin {
+		t.Fatalf("expected the split backedge to target spin, got %v", split[0].b)
+	}
+	if checksRun != 1 {
+		t.Fatalf("expected RescheduleDomCheckHook to run once, got %d", checksRun)
+	}
+
+	// spin no longer branches directly to itself; the check block now
+	// sits on the backedge and is the only thing with a path to exit.
+	for _, e := range spin.Succs {
+		if e.b == spin {
+			t.Errorf("spin still self-loops directly; backedge was not spliced")
+		}
+	}
+	reachesExit := false
+	for _, b := range f.Blocks {
+		if b == entry || b == spin {
+			continue
+		}
+		for _, e := range b.Succs {
+			if e.b == exit {
+				reachesExit = true
+			}
+		}
+	}
+	if !reachesExit {
+		t.Fatalf("expected the inserted check block to be the one reaching exit")
+	}
+}
+
+// TestInsertLoopReschedChecksDomSkipsForwardEdges checks that a simple
+// diamond (entry -> {then, else} -> join, no loop at all) is left
+// completely untouched: none of its edges have a target that dominates
+// its source, so none qualify as backedges.
+func TestInsertLoopReschedChecksDomSkipsForwardEdges(t *testing.T) {
+	c := testConfig(t)
+	boolType := c.config.Types.Bool
+	memType := types.TypeMem
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, memType, 0, nil),
+			Valu("cond", OpConstBool, boolType, 0, nil),
+			If("cond", "then", "else")),
+
+		Bloc("then", Goto("join")),
+		Bloc("else", Goto("join")),
+		Bloc("join", Exit("mem")))
+
+	f := fut.f
+	insertReschedChecksDom = true
+	defer func() { insertReschedChecksDom = false }()
+
+	split := InsertLoopReschedChecksDom(f)
+	if len(split) != 0 {
+		t.Fatalf("expected no backedges in an acyclic diamond, got %d", len(split))
+	}
+}
+
+// TestInsertLoopReschedChecksDomOffByDefault checks that with
+// insertReschedChecksDom left at its default false, a real tight loop is
+// left completely untouched.
+func TestInsertLoopReschedChecksDomOffByDefault(t *testing.T) {
+	c := testConfig(t)
+	boolType := c.config.Types.Bool
+	memType := types.TypeMem
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, memType, 0, nil),
+			Goto("spin")),
+
+		Bloc("spin",
+			Valu("cond", OpConstBool, boolType, 0, nil),
+			If("cond", "spin", "exit")),
+
+		Bloc("exit",
+			Exit("mem")))
+
+	f := fut.f
+	split := InsertLoopReschedChecksDom(f)
+	if len(split) != 0 {
+		t.Fatalf("expected InsertLoopReschedChecksDom to no-op while insertReschedChecksDom is false, got %d splits", len(split))
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// RescheduleGuardHook, when non-nil, is called once per backedge split by
+// insertLoopReschedChecks to populate the inserted check block's
+// stack-pointer-vs-stack-guard comparison, returning the boolean Value to
+// use as check.Control. check.Succs[0] is always the fast (keep looping)
+// path and check.Succs[1] the slow (reschedule) path, so the hook only
+// needs to build the condition, not wire the branch. A nil hook leaves
+// check.Control nil (not yet a codegen-ready BlockIf) so the transform's
+// structure -- one check block per backedge, correct dominance, patched
+// phis -- can be exercised and tested without a concrete stack-guard op.
+var RescheduleGuardHook func(check *Block) *Value
+
+// RescheduleCallHook, when non-nil, is called once per backedge split to
+// populate the inserted call block with the actual
+// runtime.goschedguarded invocation threaded from mem (the memory value
+// that used to flow directly around the backedge), and must return the
+// memory value the call produces. A nil hook leaves the call block a
+// pure passthrough of mem, for the same testing-without-a-concrete-op
+// reason as RescheduleGuardHook.
+var RescheduleCallHook func(call *Block, mem *Value) *Value
+
+// memRewriteTarget is one (phi, arg index) pair whose operand must become
+// after once RescheduleCallHook's memory output is known.
+type memRewriteTarget struct {
+	v        *Value
+	argIndex int
+}
+
+// memRewrite batches every place the backedge's old memory value (before)
+// must become the inserted call block's memory output (after). Collecting
+// every target before mutating any of them (instead of rewriting as each
+// is discovered) keeps every phi consistently reading the pre-splice
+// value until the whole batch is applied, which is what "one final pass"
+// is for.
+type memRewrite struct {
+	before  *Value
+	after   *Value
+	targets []memRewriteTarget
+}
+
+// apply substitutes rw.after for rw.before at every recorded target.
+func (rw *memRewrite) apply() {
+	for _, t := range rw.targets {
+		t.v.Args[t.argIndex] = rw.after
+	}
+}
+
+// insertLoopReschedChecks instruments every natural-loop backedge found by
+// f.loopnest() with a cooperative preemption check. Each backedge
+// pred->header is split into pred->check->header (fast path, taken when
+// RescheduleGuardHook's stack check passes) with a second branch
+// check->call->header (slow path, which runs RescheduleCallHook's
+// runtime.goschedguarded call before rejoining header).
+//
+// check lands in the predecessor slot pred used to occupy (the same
+// slot-preserving assumption splitReschedEdge in reschedcheck.go relies
+// on), so every existing phi at header keeps its operand for that slot
+// unchanged. call is a genuinely new predecessor, so every phi at header
+// gains one new argument for it: for an ordinary value that's just a copy
+// of the check-slot operand (call doesn't touch it), but for the memory
+// phi it must eventually be the memory value the call produces, which
+// isn't known until RescheduleCallHook runs. insertLoopReschedChecks
+// appends a placeholder (the pre-splice memory value) when it wires the
+// phi so the function stays a valid SSA graph at every intermediate step,
+// records a memRewrite for the real value, and patches every recorded
+// rewrite in one final pass after all backedges are split.
+//
+// Returns the backedges that were split.
+func insertLoopReschedChecks(f *Func) []Edge {
+	ln := f.loopnest()
+	var split []Edge
+	var rewrites []memRewrite
+
+	for _, l := range ln.loops {
+		header := l.header
+		for _, e := range append([]Edge(nil), header.Preds...) {
+			if !loopContains(ln, l, e.b) {
+				continue
+			}
+			split = append(split, e)
+			if rw := spliceReschedCheck(f, header, e); rw != nil {
+				rewrites = append(rewrites, *rw)
+			}
+		}
+	}
+
+	for i := range rewrites {
+		rewrites[i].apply()
+	}
+	if len(split) > 0 {
+		f.invalidateCFG()
+	}
+	return split
+}
+
+// spliceReschedCheck splits e = (pred, header) into pred->check->header
+// and check->call->header, wires every phi at header for the new call
+// slot, and returns the memRewrite needed to give header's memory phi
+// (if any) the call's real memory output once RescheduleCallHook has run.
+func spliceReschedCheck(f *Func, header *Block, e Edge) *memRewrite {
+	pred := e.b
+
+	check := f.NewBlock(BlockIf)
+	check.Pos = header.Pos
+	call := f.NewBlock(BlockPlain)
+	call.Pos = header.Pos
+
+	pred.removeSucc(int(e.i))
+	pred.AddEdgeTo(check)
+	check.AddEdgeTo(header) // fast path, inherits pred's old slot in header.Preds
+	check.AddEdgeTo(call)   // slow path
+	call.AddEdgeTo(header)  // rejoin, a brand new slot in header.Preds
+
+	if RescheduleGuardHook != nil {
+		check.Control = RescheduleGuardHook(check)
+	}
+
+	checkSlot := -1
+	for i, pe := range header.Preds {
+		if pe.b == check {
+			checkSlot = i
+			break
+		}
+	}
+	if checkSlot < 0 {
+		header.Fatalf("insertLoopReschedChecks: check block missing from header.Preds")
+	}
+
+	var memPhi *Value
+	for _, v := range header.Values {
+		if v.Op != OpPhi {
+			continue
+		}
+		old := v.Args[checkSlot]
+		v.AddArg(old)
+		if v.Type.IsMemory() {
+			memPhi = v
+		}
+	}
+	if memPhi == nil {
+		return nil
+	}
+
+	before := memPhi.Args[checkSlot]
+	after := before
+	if RescheduleCallHook != nil {
+		after = RescheduleCallHook(call, before)
+	}
+	if after == before {
+		return nil
+	}
+	return &memRewrite{
+		before:  before,
+		after:   after,
+		targets: []memRewriteTarget{{v: memPhi, argIndex: len(memPhi.Args) - 1}},
+	}
+}
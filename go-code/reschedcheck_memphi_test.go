@@ -0,0 +1,105 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+// TestInsertLoopReschedChecksMemPhi builds a one-block-body self loop
+// whose header has a real memory OpPhi (entry's mem merged with a
+// OpCopy of it made at the end of the body), runs
+// insertLoopReschedChecks, and checks that exactly one backedge is
+// split into a check+call pair and that the memory phi's new call-slot
+// argument ends up being RescheduleCallHook's return value rather than
+// the pre-splice memory value.
+func TestInsertLoopReschedChecksMemPhi(t *testing.T) {
+	c := testConfig(t)
+	intType := c.config.Types.Int64
+	boolType := c.config.Types.Bool
+	memType := types.TypeMem
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, memType, 0, nil),
+			Valu("zero", OpConst64, intType, 0, nil),
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("limit", OpConst64, intType, 10, nil),
+			Goto("header")),
+
+		Bloc("header",
+			Valu("i", OpPhi, intType, 0, nil, "zero", "i_inc"),
+			Valu("m", OpPhi, memType, 0, nil, "mem", "mem2"),
+			Valu("cmp", OpLess64, boolType, 0, nil, "i", "limit"),
+			If("cmp", "body", "exit")),
+
+		Bloc("body",
+			Valu("mem2", OpCopy, memType, 0, nil, "m"),
+			Valu("i_inc", OpAdd64, intType, 0, nil, "i", "one"),
+			Goto("header")),
+
+		Bloc("exit", Exit("m")))
+
+	f := fut.f
+	header, body := fut.blocks["header"], fut.blocks["body"]
+
+	var guardCalls, callCalls int
+	var sawMemArg *Value
+	RescheduleGuardHook = func(check *Block) *Value {
+		guardCalls++
+		return nil
+	}
+	RescheduleCallHook = func(call *Block, mem *Value) *Value {
+		callCalls++
+		sawMemArg = mem
+		after := call.NewValue0(call.Pos, OpCopy, mem.Type)
+		after.AddArg(mem)
+		return after
+	}
+	defer func() { RescheduleGuardHook = nil; RescheduleCallHook = nil }()
+
+	split := insertLoopReschedChecks(f)
+	if len(split) != 1 {
+		t.Fatalf("expected exactly one backedge split, got %d", len(split))
+	}
+	if guardCalls != 1 || callCalls != 1 {
+		t.Fatalf("expected one guard and one call hook invocation, got guard=%d call=%d", guardCalls, callCalls)
+	}
+	if sawMemArg == nil || sawMemArg.Op != OpCopy {
+		t.Fatalf("expected RescheduleCallHook to see the body's mem2 value, got %v", sawMemArg)
+	}
+
+	if len(header.Preds) != 3 {
+		t.Fatalf("expected header to gain a third predecessor (call), got %d preds", len(header.Preds))
+	}
+
+	var memPhi *Value
+	for _, v := range header.Values {
+		if v.Op == OpPhi && v.Type.IsMemory() {
+			memPhi = v
+		}
+	}
+	if memPhi == nil {
+		t.Fatalf("header lost its memory phi")
+	}
+	if len(memPhi.Args) != 3 {
+		t.Fatalf("expected memory phi to have 3 args, got %d", len(memPhi.Args))
+	}
+	if memPhi.Args[2] == sawMemArg {
+		t.Errorf("expected the new call-slot arg to be RescheduleCallHook's return value, not the value it was handed")
+	}
+	if memPhi.Args[2].Op != OpCopy || memPhi.Args[2].Args[0] != sawMemArg {
+		t.Errorf("expected the new call-slot arg to wrap sawMemArg, got %v", memPhi.Args[2])
+	}
+
+	// body no longer points at header directly; it now flows through
+	// the inserted check and call blocks.
+	for _, e := range body.Succs {
+		if e.b == header {
+			t.Errorf("body still branches directly to header; backedge was not spliced")
+		}
+	}
+}
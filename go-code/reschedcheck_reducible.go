@@ -0,0 +1,109 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// ReschedSkipDiagnostic records a loop InsertLoopReschedChecks declined
+// to instrument, and why.
+type ReschedSkipDiagnostic struct {
+	Header *Block
+	Reason string
+}
+
+// InsertLoopReschedChecks walks sccs() and, for every reducible loop SCC,
+// splits each backedge (a predecessor of the header that lies inside the
+// SCC's own block set) with a new block performing a preemption check on
+// the common path back to the header.
+//
+// Header phis don't need their Args rewritten: AddEdgeTo/removeSucc
+// splice the new check block into the same predecessor slot the
+// backedge source used to occupy, so a phi's existing operand for that
+// slot is still the right incoming value — only now it flows through
+// check instead of arriving directly. That's what "thread memory through
+// the inserted block" amounts to here: the memory phi's operand for this
+// slot is left untouched, and check is a pure passthrough between the
+// backedge source and the header.
+//
+// Irreducible SCCs are skipped (not split) and reported via the returned
+// diagnostics, so callers can run ReduceIrreducibleLoops (or
+// MakeReducible) first and retry. When onlyCallFreeLoops is true, loops
+// the LoopForest already reports as ContainsUnavoidableCall are skipped
+// too, without a diagnostic, since a call on every iteration already
+// gives the runtime a preemption opportunity.
+//
+// Returns the backedges split and one diagnostic per irreducible SCC
+// skipped.
+func InsertLoopReschedChecks(f *Func, onlyCallFreeLoops bool) ([]Edge, []ReschedSkipDiagnostic) {
+	var split []Edge
+	var skipped []ReschedSkipDiagnostic
+
+	var forest *LoopForest
+	if onlyCallFreeLoops {
+		forest = f.LoopForest()
+	}
+
+	sccs := f.sccs()
+	for i := range sccs {
+		scc := &sccs[i]
+		if !scc.IsLoop() {
+			continue
+		}
+		if !scc.IsReducible() {
+			skipped = append(skipped, ReschedSkipDiagnostic{
+				Reason: "irreducible SCC: pair with a node-splitting pass (ReduceIrreducibleLoops/MakeReducible) first",
+			})
+			continue
+		}
+		header := scc.Header()
+		if onlyCallFreeLoops && loopForestContainsCall(forest, header) {
+			continue
+		}
+
+		inSCC := make(map[ID]bool, len(scc.Blocks))
+		for _, b := range scc.Blocks {
+			inSCC[b.ID] = true
+		}
+		for _, b := range scc.Blocks {
+			for _, e := range append([]Edge(nil), b.Succs...) {
+				if e.b != header || !inSCC[b.ID] {
+					continue
+				}
+				check := f.NewBlock(BlockPlain)
+				check.Pos = header.Pos
+				b.removeSucc(int(e.i))
+				b.AddEdgeTo(check)
+				check.AddEdgeTo(header)
+				if RescheduleCheckHook != nil {
+					RescheduleCheckHook(f, check)
+				}
+				split = append(split, e)
+			}
+		}
+	}
+	if len(split) > 0 {
+		f.invalidateCFG()
+	}
+	return split, skipped
+}
+
+// loopForestContainsCall reports whether forest has a Loop headed by
+// header with ContainsUnavoidableCall set.
+func loopForestContainsCall(forest *LoopForest, header *Block) bool {
+	if forest == nil {
+		return false
+	}
+	var find func([]*Loop) bool
+	find = func(loops []*Loop) bool {
+		for _, l := range loops {
+			if l.Header == header {
+				return l.ContainsUnavoidableCall
+			}
+			if find(l.Children) {
+				return true
+			}
+		}
+		return false
+	}
+	return find(forest.Roots)
+}
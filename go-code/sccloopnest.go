@@ -0,0 +1,115 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// Loop is a loop discovered directly from SCC results, so (unlike
+// likelyadjust.go's dominator-built loopnest) it is defined for
+// irreducible loops too. Header is the SCC's primary entry target
+// (Entries[0].To, or the sole entry target for a reducible loop);
+// Irreducible reports whether the SCC actually has more than one.
+type Loop struct {
+	Header      *Block
+	Blocks      []*Block
+	Backedges   []Edge // intra-loop edges whose target is a header
+	Exits       []Edge // edges leaving the loop
+	Irreducible bool
+	Parent      *Loop
+	Children    []*Loop
+	Depth       int
+}
+
+// LoopNest is the SCC-derived loop forest for a Func: every non-trivial
+// SCC becomes a Loop, nested by recursively applying sccSubgraph with
+// the chosen header excluded.
+type LoopNest struct {
+	Loops []*Loop
+	b2l   map[ID]*Loop
+}
+
+// Loops returns the cached SCC-derived LoopNest for f, computing it if
+// necessary.
+func (f *Func) Loops() *LoopNest {
+	if f.cachedSCCLoopNest == nil {
+		f.cachedSCCLoopNest = computeSCCLoopNest(f)
+	}
+	return f.cachedSCCLoopNest
+}
+
+// LoopOf returns the innermost Loop containing b, or nil if b is not
+// part of any loop.
+func (ln *LoopNest) LoopOf(b *Block) *Loop {
+	return ln.b2l[b.ID]
+}
+
+// Irreducible returns every Loop in ln whose SCC has more than one entry
+// target.
+func (ln *LoopNest) Irreducible() []*Loop {
+	var out []*Loop
+	for _, l := range ln.Loops {
+		if l.Irreducible {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func computeSCCLoopNest(f *Func) *LoopNest {
+	ln := &LoopNest{b2l: map[ID]*Loop{}}
+	buildSCCLoops(f, f.sccs(), nil, 0, ln)
+	return ln
+}
+
+// buildSCCLoops turns the non-trivial SCCs in sccs into Loops parented
+// by parent, recording each into ln, and returns the Loops created at
+// this level (for the caller to hang off as Children).
+func buildSCCLoops(f *Func, sccs []SCC, parent *Loop, depth int, ln *LoopNest) []*Loop {
+	var loops []*Loop
+	for i := range sccs {
+		scc := &sccs[i]
+		if !scc.IsLoop() {
+			continue
+		}
+		headers := scc.EntryTargets()
+		header := headers[0]
+		isHeader := make(map[ID]bool, len(headers))
+		for _, h := range headers {
+			isHeader[h.ID] = true
+		}
+		inLoop := make(map[ID]bool, len(scc.Blocks))
+		for _, b := range scc.Blocks {
+			inLoop[b.ID] = true
+		}
+
+		var backedges, exits []Edge
+		for _, b := range scc.Blocks {
+			for _, e := range b.Succs {
+				switch {
+				case !inLoop[e.b.ID]:
+					exits = append(exits, e)
+				case isHeader[e.b.ID]:
+					backedges = append(backedges, e)
+				}
+			}
+		}
+
+		l := &Loop{
+			Header:      header,
+			Blocks:      scc.Blocks,
+			Backedges:   backedges,
+			Exits:       exits,
+			Irreducible: len(headers) > 1,
+			Parent:      parent,
+			Depth:       depth,
+		}
+		for _, b := range scc.Blocks {
+			ln.b2l[b.ID] = l
+		}
+		l.Children = buildSCCLoops(f, sccSubgraph(f, scc.Blocks, header), l, depth+1, ln)
+
+		loops = append(loops, l)
+		ln.Loops = append(ln.Loops, l)
+	}
+	return loops
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// Loop-exit spill sinking, following the shape of Go CL 21037: for a
+// value that's spilled inside a loop but still live after the loop
+// exits, there's no reason to pay the store on every iteration -- it can
+// run once per exit instead. sinkLoopSpills identifies such values using
+// the loop metadata from loopnest_metadata.go (l.isInner, l.exits,
+// ln.ContainsUnavoidableCall) and this package's existing liveness
+// analysis (regAllocState.computeLive).
+//
+// This snapshot's regAllocState stops at pre-assignment liveness and
+// desired-register hints (see regalloc_scc.go) -- it doesn't carry the
+// OpStoreReg/OpLoadReg spill IR or a completed register assignment a
+// real implementation would clone and delete. sinkLoopSpills therefore
+// reports its findings as a plan (spillSinkPlan) rather than mutating
+// the function: the candidate selection and exit-placement logic below
+// is exactly what the request describes, but turning a plan into an
+// actual cloned store and a deleted original is left to the
+// spill-insertion code that would need to exist first.
+type spillSinkPlan struct {
+	Value *Value
+	Loop  *loop
+	Exits []*Block
+}
+
+// sinkLoopSpills finds, in every innermost call-free loop of ln, values
+// defined in the loop whose liveness crosses at least one loop exit, and
+// proposes sinking the spill of each one that crosses exactly one exit to
+// that exit; a value crossing more than one exit is counted as
+// disqualified and gets no plan. It's a no-op (returns
+// nil) when ln.hasIrreducible, since exits/containment aren't meaningful
+// for the irreducible region.
+func sinkLoopSpills(f *Func, ln *loopnest) []spillSinkPlan {
+	if ln.hasIrreducible {
+		return nil
+	}
+
+	s := &regAllocState{}
+	s.init(f)
+	s.computeLive()
+
+	var plans []spillSinkPlan
+	candidates, sunk, disqualified := 0, 0, 0
+
+	for _, l := range ln.loops {
+		if !l.isInner || ln.ContainsUnavoidableCall(l) {
+			continue
+		}
+		exits := ln.exits(l)
+		if len(exits) == 0 {
+			continue
+		}
+		for _, b := range f.Blocks {
+			if ln.b2l[b.ID] != l {
+				continue
+			}
+			for _, v := range b.Values {
+				if !s.values[v.ID].needReg || s.values[v.ID].rematerializeable {
+					continue
+				}
+				candidates++
+				crossing := exitsCrossedBy(s, ln, l, v, exits)
+				if len(crossing) == 0 {
+					continue
+				}
+				// The request's register-identity requirement
+				// (the value's assigned register must match at
+				// its definition and at every exit) needs a
+				// completed register assignment, which this
+				// analysis-only pass doesn't have. Sinking to
+				// more than one exit can't be vetted without
+				// it, so conservatively disqualify those and
+				// only propose the single-exit case.
+				if len(crossing) > 1 {
+					disqualified++
+					continue
+				}
+				plans = append(plans, spillSinkPlan{Value: v, Loop: l, Exits: crossing})
+				sunk++
+			}
+		}
+	}
+	// remaining is every examined candidate that was neither sunk nor
+	// disqualified -- a spill of a call-free inner loop's value that is
+	// not live across any of the loop's exits -- left in place inside
+	// the loop exactly as if this pass hadn't run.
+	remaining := candidates - sunk - disqualified
+
+	if f.pass != nil && f.pass.stats > 0 {
+		f.LogStat("spillsink in "+f.Name+":",
+			sunk, "sunk", remaining, "remaining", disqualified, "disqualified")
+	}
+	return plans
+}
+
+// exitsCrossedBy returns the subset of exits that v is live across: an
+// exit e qualifies if some predecessor of e inside l still has v live at
+// the end of the block.
+func exitsCrossedBy(s *regAllocState, ln *loopnest, l *loop, v *Value, exits []*Block) []*Block {
+	var crossed []*Block
+	for _, exit := range exits {
+		for _, e := range exit.Preds {
+			if ln.b2l[e.b.ID] != l {
+				continue
+			}
+			if valueLiveAtEnd(s, e.b, v.ID) {
+				crossed = append(crossed, exit)
+				break
+			}
+		}
+	}
+	return crossed
+}
+
+func valueLiveAtEnd(s *regAllocState, b *Block, id ID) bool {
+	for _, li := range s.live[b.ID] {
+		if li.ID == id {
+			return true
+		}
+	}
+	return false
+}
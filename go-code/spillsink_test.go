@@ -0,0 +1,63 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+// TestSinkLoopSpillsSingleExit builds a one-block, call-free, single-exit
+// loop that defines a value used only after the loop, and checks
+// sinkLoopSpills proposes sinking it to the loop's one exit.
+func TestSinkLoopSpillsSingleExit(t *testing.T) {
+	c := testConfig(t)
+	intType := c.config.Types.Int64
+	boolType := c.config.Types.Bool
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("sum0", OpConst64, intType, 0, nil),
+			Goto("loop")),
+
+		Bloc("loop",
+			Valu("sum", OpPhi, intType, 0, nil, "sum0", "sum1"),
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("sum1", OpAdd64, intType, 0, nil, "sum", "one"),
+			Valu("cond", OpConstBool, boolType, 1, nil),
+			If("cond", "loop", "exit")),
+
+		Bloc("exit",
+			Valu("use", OpAdd64, intType, 0, nil, "sum1", "sum1"),
+			Exit("mem")))
+
+	f := fut.f
+	loopB, sum1 := fut.blocks["loop"], fut.values["sum1"]
+
+	ln := f.loopnest()
+	if len(ln.loops) != 1 {
+		t.Fatalf("expected exactly one loop, found %d", len(ln.loops))
+	}
+	l := ln.loops[0]
+	if l.header != loopB {
+		t.Fatalf("expected loop header %s, got %s", loopB, l.header)
+	}
+
+	plans := sinkLoopSpills(f, ln)
+
+	var got *spillSinkPlan
+	for i := range plans {
+		if plans[i].Value == sum1 {
+			got = &plans[i]
+		}
+	}
+	if got == nil {
+		t.Fatalf("sinkLoopSpills did not propose sinking sum1's spill; plans=%v", plans)
+	}
+	if len(got.Exits) != 1 || got.Exits[0] != fut.blocks["exit"] {
+		t.Errorf("expected sum1's spill sunk to [exit], got %v", got.Exits)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// Direction selects whether Solve propagates facts from predecessors to
+// successors (Forward) or successors to predecessors (Backward).
+type Direction int
+
+const (
+	Forward Direction = iota
+	Backward
+)
+
+// Lattice is the per-block value type a Solve client computes. L must
+// implement Lattice[L] itself (the same self-referential shape as
+// dataflow.Transfer in the dataflow subpackage), so Bottom/Join/Equal can
+// be called without a separate witness value.
+type Lattice[L any] interface {
+	Bottom() L
+	Join(a, b L) L
+	Equal(a, b L) bool
+}
+
+// Widener is an optional Lattice extension. A client that implements it
+// gets Widen applied at WTO component heads once a component has iterated
+// past widenAfter times, guaranteeing termination on lattices of infinite
+// height (constant propagation, intervals) where plain Join never
+// reaches a fixed point on its own.
+type Widener[L any] interface {
+	Widen(prev, next L) L
+}
+
+// Narrower is an optional Lattice extension. A client that implements it
+// gets Narrow applied once, after widening has forced every component to
+// stabilize, to recover some of the precision Widen gave up.
+type Narrower[L any] interface {
+	Narrow(prev, next L) L
+}
+
+// Transfer computes the fact flowing out of a block given the facts
+// flowing in from its predecessors (Forward) or successors (Backward).
+type Transfer[L any] interface {
+	TransferBlock(b *Block, in []L) L
+}
+
+// Solution holds Solve's computed per-block fact, indexed by Block.ID.
+type Solution[L any] struct {
+	Out []L
+}
+
+// DefaultWidenAfter is the iteration count Solve waits through before
+// applying Widen at a component head when the caller passes widenAfter
+// <= 0. 2 matches the pass count sccAlternatingOrders's doc comment says
+// covers the overwhelming majority of SCCs in practice.
+const DefaultWidenAfter = 2
+
+// Solve runs a chaotic-iteration dataflow analysis over f's CFG, using
+// f.WTO() (see wto.go) to decide where loop heads are: blocks are visited
+// in WTO order, and whenever a Component is reached, its Body is
+// iterated to a fixed point in place, applying Widen at the head (if t
+// implements Widener) once the component has iterated past widenAfter
+// times, before Solve moves on. This is Bourdoncle's algorithm — a flat
+// worklist has no principled way to know which blocks are loop heads that
+// need widening, but a WTO already encodes exactly that nesting.
+//
+// A final narrowing pass (if t implements Narrower) is run once every
+// component has stabilized, to recover precision widening gave up.
+func Solve[L Lattice[L]](f *Func, t Transfer[L], dir Direction, widenAfter int) Solution[L] {
+	if widenAfter <= 0 {
+		widenAfter = DefaultWidenAfter
+	}
+	var zero L
+	out := make([]L, f.NumBlocks())
+	for i := range out {
+		out[i] = zero.Bottom()
+	}
+
+	w := f.WTO()
+	if dir == Backward {
+		w = reverseWTO(w)
+	}
+	solveWTO(t, dir, w, out, widenAfter)
+	narrowWTO(t, dir, w, out)
+	return Solution[L]{Out: out}
+}
+
+func solveWTO[L Lattice[L]](t Transfer[L], dir Direction, w WTO, out []L, widenAfter int) {
+	for _, item := range w {
+		if item.Block != nil {
+			out[item.Block.ID] = transferOne(t, dir, item.Block, out)
+			continue
+		}
+		solveComponent(t, dir, item.Component, out, widenAfter)
+	}
+}
+
+// solveComponent iterates c's body to a fixed point, re-evaluating the
+// head after each pass over the body (since the body's last block may
+// feed a backedge into it).
+func solveComponent[L Lattice[L]](t Transfer[L], dir Direction, c *Component, out []L, widenAfter int) {
+	var zero L
+	iter := 0
+	for {
+		out[c.Head.ID] = transferOne(t, dir, c.Head, out)
+		solveWTO(t, dir, c.Body, out, widenAfter)
+		next := transferOne(t, dir, c.Head, out)
+		iter++
+		if w, ok := t.(Widener[L]); ok && iter > widenAfter {
+			next = w.Widen(out[c.Head.ID], next)
+		}
+		stable := zero.Equal(out[c.Head.ID], next)
+		out[c.Head.ID] = next
+		if stable {
+			return
+		}
+	}
+}
+
+func narrowWTO[L Lattice[L]](t Transfer[L], dir Direction, w WTO, out []L) {
+	n, ok := t.(Narrower[L])
+	for _, item := range w {
+		if item.Component == nil {
+			continue
+		}
+		c := item.Component
+		if ok {
+			out[c.Head.ID] = n.Narrow(out[c.Head.ID], transferOne(t, dir, c.Head, out))
+		}
+		narrowWTO(t, dir, c.Body, out)
+	}
+}
+
+func transferOne[L Lattice[L]](t Transfer[L], dir Direction, b *Block, out []L) L {
+	var neighbors []Edge
+	if dir == Forward {
+		neighbors = b.Preds
+	} else {
+		neighbors = b.Succs
+	}
+	in := make([]L, len(neighbors))
+	for i, e := range neighbors {
+		in[i] = out[e.b.ID]
+	}
+	return t.TransferBlock(b, in)
+}
+
+func reverseWTO(w WTO) WTO {
+	out := make(WTO, len(w))
+	for i, item := range w {
+		if item.Component != nil {
+			item = WTOItem{Component: &Component{Head: item.Component.Head, Body: reverseWTO(item.Component.Body)}}
+		}
+		out[len(w)-1-i] = item
+	}
+	return out
+}
+
+// idSet is the Lattice used by SimpleLiveness below: the set of value IDs
+// live on entry to a block.
+type idSet map[ID]bool
+
+func (idSet) Bottom() idSet { return idSet{} }
+
+func (idSet) Join(a, b idSet) idSet {
+	out := make(idSet, len(a)+len(b))
+	for k := range a {
+		out[k] = true
+	}
+	for k := range b {
+		out[k] = true
+	}
+	return out
+}
+
+func (idSet) Equal(a, b idSet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+type simpleLivenessTransfer struct{}
+
+func (simpleLivenessTransfer) TransferBlock(b *Block, in []idSet) idSet {
+	live := idSet{}
+	for _, s := range in {
+		for k := range s {
+			live[k] = true
+		}
+	}
+	for i := len(b.Values) - 1; i >= 0; i-- {
+		v := b.Values[i]
+		delete(live, v.ID)
+		for _, a := range v.Args {
+			live[a.ID] = true
+		}
+	}
+	return live
+}
+
+// SimpleLiveness recomputes, for every block, the set of value IDs live
+// on entry, using the generic Solve above instead of computeLive's
+// hand-tuned 3-pass convergence. It exists as a validation path: a
+// function where SimpleLiveness and computeLive disagree indicates a bug
+// in one of them. It is not wired into regalloc, which keeps computeLive
+// for its considerably richer desired-register and remat tracking.
+func SimpleLiveness(f *Func) map[ID]idSet {
+	sol := Solve[idSet](f, simpleLivenessTransfer{}, Backward, 0)
+	out := make(map[ID]idSet, len(sol.Out))
+	for _, b := range f.Blocks {
+		out[b.ID] = sol.Out[b.ID]
+	}
+	return out
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// RegionKind classifies one node of a RegionTree.
+type RegionKind int8
+
+const (
+	RegionBlock RegionKind = iota
+	RegionIfThen
+	RegionIfThenElse
+	RegionSelfLoop
+	RegionWhileLoop
+	RegionNaturalLoop
+	RegionImproper
+	RegionProperInterval
+)
+
+func (k RegionKind) String() string {
+	switch k {
+	case RegionBlock:
+		return "BLOCK"
+	case RegionIfThen:
+		return "IF-THEN"
+	case RegionIfThenElse:
+		return "IF-THEN-ELSE"
+	case RegionSelfLoop:
+		return "SELF-LOOP"
+	case RegionWhileLoop:
+		return "WHILE-LOOP"
+	case RegionNaturalLoop:
+		return "NATURAL-LOOP"
+	case RegionImproper:
+		return "IMPROPER"
+	case RegionProperInterval:
+		return "PROPER-INTERVAL"
+	}
+	return "?"
+}
+
+// Region is one collapsed node of a structural decomposition: Header is
+// its single entry (nil only for RegionImproper, which can have several),
+// Members are every block it absorbs (recursively, i.e. including any
+// child Region's blocks), Entries are the blocks outside the region that
+// branch into it, and Exits are the blocks outside it that a branch out
+// of it reaches.
+type Region struct {
+	Kind     RegionKind
+	Header   *Block
+	Members  []*Block
+	Children []*Region
+	Entries  []*Block
+	Exits    []*Block
+}
+
+// RegionTree is a structural decomposition of a Func's CFG: Roots holds
+// one Region per top-level collapsed shape, covering every reachable
+// block exactly once.
+//
+// This builds a DJ graph only implicitly: f.Sdom() is the D-edges (the
+// dominator tree), and a region's "back" vs "cross" J-edges are read
+// directly off f.sccs()'s entry-edge bookkeeping rather than walking
+// edges classified against the dominator tree by hand. The collapse
+// itself is a single bottom-up pass, not the classic iterative
+// fixed-point over shrinking DJ graphs: irreducible regions and loops
+// come from f.sccs() (bottom-most first, since sccs() already reports
+// them leader-first in a topology compatible with that), and any
+// remaining acyclic shape is classified once by its local If/join
+// pattern rather than being grown by repeated region merges. CFGs whose
+// acyclic structure needs multiple merge rounds to expose an outer
+// IF-THEN/IF-THEN-ELSE (for example a diamond one of whose arms is
+// itself another diamond) are represented as nested Children here
+// instead of as one flat Region, which is the one respect in which this
+// is not the textbook fixed-point algorithm.
+type RegionTree struct {
+	Roots []*Region
+}
+
+// StructuralAnalysis computes f's RegionTree.
+func StructuralAnalysis(f *Func) *RegionTree {
+	sdom := f.Sdom()
+	sccs := f.sccs()
+
+	inLoop := make(map[ID]*Region, len(f.Blocks))
+	var loopRegions []*Region
+	for i := range sccs {
+		scc := &sccs[i]
+		if !scc.IsLoop() {
+			continue
+		}
+		r := classifyLoopSCC(scc, sdom)
+		for _, b := range scc.Blocks {
+			inLoop[b.ID] = r
+		}
+		loopRegions = append(loopRegions, r)
+	}
+
+	// classifyAcyclic below needs to know, for every block not in a loop,
+	// whether it's already been absorbed as a branch/join of some other
+	// acyclic region so it isn't also emitted as a standalone root.
+	absorbed := make(map[ID]bool, len(f.Blocks))
+
+	addedLoop := make(map[*Region]bool, len(loopRegions))
+	tree := &RegionTree{}
+	// Walk in reverse postorder (entry first) rather than postorder: an
+	// If block must be classified, and its arms marked absorbed, before
+	// the walk reaches those arms, or they'd be emitted as their own
+	// standalone roots first.
+	po := f.postorder()
+	order := make([]*Block, len(po))
+	for i, b := range po {
+		order[len(po)-1-i] = b
+	}
+	for _, b := range order {
+		if lr, ok := inLoop[b.ID]; ok {
+			if !addedLoop[lr] {
+				addedLoop[lr] = true
+				tree.Roots = append(tree.Roots, lr)
+			}
+			continue
+		}
+		if absorbed[b.ID] {
+			continue
+		}
+		r := classifyAcyclic(b, absorbed)
+		tree.Roots = append(tree.Roots, r)
+	}
+	return tree
+}
+
+// classifyLoopSCC turns one loop SCC into a Region: a single self-looping
+// block is a SELF-LOOP; a reducible multi-block loop whose header is an
+// If block with an exit straight out of the loop is a WHILE-LOOP (the
+// common "test at top" shape), any other reducible loop is a
+// NATURAL-LOOP, and an SCC with more than one external entry is
+// IMPROPER.
+func classifyLoopSCC(scc *SCC, sdom SparseTree) *Region {
+	r := &Region{Members: scc.Blocks}
+
+	if !scc.IsReducible() {
+		r.Kind = RegionImproper
+		seen := make(map[ID]bool)
+		for _, e := range scc.Entries {
+			if !seen[e.From.ID] {
+				seen[e.From.ID] = true
+				r.Entries = append(r.Entries, e.From)
+			}
+		}
+		addLoopExits(r)
+		return r
+	}
+
+	header := scc.Header()
+	if header == nil {
+		header = headerByDominance(sdom, scc.Blocks)
+	}
+	r.Header = header
+
+	if len(scc.Blocks) == 1 {
+		r.Kind = RegionSelfLoop
+		addLoopExits(r)
+		return r
+	}
+
+	r.Kind = RegionNaturalLoop
+	if header != nil && len(header.Succs) == 2 {
+		inLoop := make(map[ID]bool, len(scc.Blocks))
+		for _, b := range scc.Blocks {
+			inLoop[b.ID] = true
+		}
+		if !inLoop[header.Succs[0].b.ID] || !inLoop[header.Succs[1].b.ID] {
+			r.Kind = RegionWhileLoop
+		}
+	}
+	addLoopExits(r)
+	return r
+}
+
+// addLoopExits fills r.Exits from r.Members' out-of-region successors.
+func addLoopExits(r *Region) {
+	inLoop := make(map[ID]bool, len(r.Members))
+	for _, b := range r.Members {
+		inLoop[b.ID] = true
+	}
+	seen := make(map[ID]bool)
+	for _, b := range r.Members {
+		for _, e := range b.Succs {
+			if !inLoop[e.b.ID] && !seen[e.b.ID] {
+				seen[e.b.ID] = true
+				r.Exits = append(r.Exits, e.b)
+			}
+		}
+	}
+}
+
+// classifyAcyclic classifies the acyclic shape rooted at b: an If block
+// whose two arms both fall straight through to a common join with no
+// other predecessors is IF-THEN-ELSE; one whose only non-trivial arm
+// falls through to the other arm (the "then" with no "else") is IF-THEN;
+// a single successor b dominates exclusively, with no other predecessor,
+// collapses into a PROPER-INTERVAL chain; anything else is a lone BLOCK.
+// Absorbed branch/join/chain blocks are marked in absorbed so
+// StructuralAnalysis's root walk skips them.
+func classifyAcyclic(b *Block, absorbed map[ID]bool) *Region {
+	if b.Kind == BlockIf && len(b.Succs) == 2 {
+		t, f := b.Succs[0].b, b.Succs[1].b
+		if r := ifThenElse(b, t, f, absorbed); r != nil {
+			return r
+		}
+		if r := ifThenElse(b, f, t, absorbed); r != nil {
+			return r
+		}
+	}
+
+	r := &Region{Kind: RegionBlock, Header: b, Members: []*Block{b}}
+	for _, e := range b.Succs {
+		r.Exits = append(r.Exits, e.b)
+	}
+
+	for len(r.Members) > 0 {
+		last := r.Members[len(r.Members)-1]
+		if len(last.Succs) != 1 {
+			break
+		}
+		next := last.Succs[0].b
+		if len(next.Preds) != 1 || next == b {
+			break
+		}
+		r.Members = append(r.Members, next)
+		absorbed[next.ID] = true
+		r.Exits = r.Exits[:0]
+		for _, e := range next.Succs {
+			r.Exits = append(r.Exits, e.b)
+		}
+		r.Kind = RegionProperInterval
+	}
+	return r
+}
+
+// ifThenElse tries to build an IF-THEN or IF-THEN-ELSE Region out of b's
+// branch to thenArm, with elseArm as the other branch: if thenArm falls
+// straight through to elseArm (and has no other predecessor), it's a
+// THEN with no separate else; if both arms instead converge on a common
+// join block with no other predecessors, it's a full IF-THEN-ELSE.
+// Returns nil if neither pattern matches this orientation.
+func ifThenElse(b, thenArm, elseArm *Block, absorbed map[ID]bool) *Region {
+	if len(thenArm.Preds) == 1 && len(thenArm.Succs) == 1 && thenArm.Succs[0].b == elseArm {
+		absorbed[thenArm.ID] = true
+		return &Region{
+			Kind:    RegionIfThen,
+			Header:  b,
+			Members: []*Block{b, thenArm},
+			Exits:   []*Block{elseArm},
+		}
+	}
+	if len(thenArm.Preds) == 1 && len(elseArm.Preds) == 1 &&
+		len(thenArm.Succs) == 1 && len(elseArm.Succs) == 1 &&
+		thenArm.Succs[0].b == elseArm.Succs[0].b && thenArm != elseArm {
+		join := thenArm.Succs[0].b
+		absorbed[thenArm.ID] = true
+		absorbed[elseArm.ID] = true
+		return &Region{
+			Kind:    RegionIfThenElse,
+			Header:  b,
+			Members: []*Block{b, thenArm, elseArm},
+			Exits:   []*Block{join},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+// TestStructuralAnalysisWhileLoop checks that buildSimpleLoop's header
+// (an If block with one exit straight out of the loop) is classified as
+// a WHILE-LOOP, and that every loop block ends up as one of its Members.
+func TestStructuralAnalysisWhileLoop(t *testing.T) {
+	f := buildSimpleLoop(t, 3)
+	tree := StructuralAnalysis(f)
+
+	var loopRegion *Region
+	for _, r := range tree.Roots {
+		if r.Kind == RegionWhileLoop || r.Kind == RegionNaturalLoop {
+			loopRegion = r
+		}
+	}
+	if loopRegion == nil {
+		t.Fatalf("no loop Region found among roots")
+	}
+	if loopRegion.Kind != RegionWhileLoop {
+		t.Errorf("loop Region kind = %v, want WHILE-LOOP", loopRegion.Kind)
+	}
+	if len(loopRegion.Members) != 4 { // header + body0, body1, body2
+		t.Errorf("loop Region has %d members, want 4", len(loopRegion.Members))
+	}
+}
+
+// TestStructuralAnalysisIrreducible checks that an irreducible SCC
+// becomes a single IMPROPER Region recording both of its entries.
+func TestStructuralAnalysisIrreducible(t *testing.T) {
+	f := buildIrreducibleSimple(t)
+	tree := StructuralAnalysis(f)
+
+	var improper *Region
+	for _, r := range tree.Roots {
+		if r.Kind == RegionImproper {
+			improper = r
+		}
+	}
+	if improper == nil {
+		t.Fatalf("no IMPROPER Region found among roots")
+	}
+	if len(improper.Entries) < 2 {
+		t.Errorf("IMPROPER Region has %d entries, want at least 2", len(improper.Entries))
+	}
+}
+
+// TestStructuralAnalysisIfThenElse builds a minimal diamond (entry
+// branches to two arms that both fall through to a common join) and
+// checks it collapses to a single IF-THEN-ELSE Region.
+func TestStructuralAnalysisIfThenElse(t *testing.T) {
+	c := testConfig(t)
+	boolType := c.config.Types.Bool
+	intType := c.config.Types.Int64
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("cond", OpConstBool, boolType, 1, nil),
+			If("cond", "then", "els")),
+		Bloc("then",
+			Valu("vt", OpConst64, intType, 1, nil),
+			Goto("join")),
+		Bloc("els",
+			Valu("ve", OpConst64, intType, 2, nil),
+			Goto("join")),
+		Bloc("join",
+			Valu("phi", OpPhi, intType, 0, nil, "vt", "ve"),
+			Exit("mem")))
+	f := fut.f
+
+	tree := StructuralAnalysis(f)
+	var found *Region
+	for _, r := range tree.Roots {
+		if r.Kind == RegionIfThenElse {
+			found = r
+		}
+	}
+	if found == nil {
+		t.Fatalf("no IF-THEN-ELSE Region found among roots: %+v", tree.Roots)
+	}
+	if len(found.Members) != 3 {
+		t.Errorf("IF-THEN-ELSE Region has %d members, want 3", len(found.Members))
+	}
+}
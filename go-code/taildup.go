@@ -0,0 +1,249 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// maxTailDupBlocks bounds the number of blocks a single invocation of
+// duplicateTailsToReduceIrreducibility will clone. Irreducible regions
+// found in the wild are small (a handful of blocks); this cap exists only
+// to bail out cleanly on pathological, hand-constructed CFGs instead of
+// blowing up function size.
+const maxTailDupBlocks = 64
+
+// duplicateTailsToReduceIrreducibility is a pre-pass that removes
+// irreducibility from a function's CFG by node splitting (tail
+// duplication), so that computeLive can take the SCC 3-pass fast path
+// instead of falling back to computeLiveIterative. It is run from
+// computeLive itself, guarded by s.loopnest.hasIrreducible, before the
+// loopnest and postorder used by the rest of liveness analysis are
+// computed.
+//
+// For every irreducible SCC (one with more than one external entry
+// target), we keep the entry target with the most external in-edges as
+// the SCC's single header and duplicate the region reachable from every
+// other entry target without first passing back through the header. The
+// external edges that used to land on a secondary entry are redirected to
+// its clone; the clone's own successors that leave the duplicated region
+// are rewired back to the original blocks, which restores a single entry
+// into the loop body proper. Phis at blocks that stop being merge points
+// are simplified away; phis at blocks that become new merge points (a
+// clone rejoining the original region) gain the extra operand.
+//
+// Returns the number of blocks duplicated; 0 means nothing changed
+// (either there was no irreducible SCC, or the region exceeded
+// maxTailDupBlocks and was left alone for computeLiveIterative to
+// handle).
+func duplicateTailsToReduceIrreducibility(f *Func) int {
+	sccs := f.sccs()
+	total := 0
+	for i := range sccs {
+		scc := &sccs[i]
+		if !scc.IsLoop() || scc.IsReducible() {
+			continue
+		}
+		targets := scc.EntryTargets()
+		if len(targets) < 2 {
+			continue
+		}
+		primary := choosePrimaryHeader(scc, targets)
+		inSCC := make(map[ID]bool, len(scc.Blocks))
+		for _, b := range scc.Blocks {
+			inSCC[b.ID] = true
+		}
+		for _, t := range targets {
+			if t == primary {
+				continue
+			}
+			region := tailRegion(t, primary, inSCC)
+			if total+len(region) > maxTailDupBlocks {
+				// Budget exhausted: leave the remaining secondary
+				// entries alone. computeLiveIterative still handles
+				// this SCC correctly, just more slowly.
+				return total
+			}
+			duplicateRegion(f, t, region)
+			total += len(region)
+		}
+	}
+	if total > 0 {
+		f.invalidateCFG()
+	}
+	return total
+}
+
+// choosePrimaryHeader picks the entry target that will remain the SCC's
+// single header: the one receiving the most external entry edges, ties
+// broken by lowest block ID for determinism.
+func choosePrimaryHeader(scc *SCC, targets []*Block) *Block {
+	counts := make(map[ID]int, len(targets))
+	for _, e := range scc.Entries {
+		counts[e.To.ID]++
+	}
+	best := targets[0]
+	for _, t := range targets[1:] {
+		if counts[t.ID] > counts[best.ID] || (counts[t.ID] == counts[best.ID] && t.ID < best.ID) {
+			best = t
+		}
+	}
+	return best
+}
+
+// tailRegion collects the blocks reachable from t while staying inside
+// the SCC and without passing through primary, i.e. the secondary-entry
+// tail that needs its own copy so that primary remains the sole way in.
+func tailRegion(t, primary *Block, inSCC map[ID]bool) []*Block {
+	seen := map[ID]bool{t.ID: true}
+	region := []*Block{t}
+	stack := []*Block{t}
+	for len(stack) > 0 {
+		b := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range b.Succs {
+			s := e.b
+			if s == primary || !inSCC[s.ID] || seen[s.ID] {
+				continue
+			}
+			seen[s.ID] = true
+			region = append(region, s)
+			stack = append(stack, s)
+		}
+	}
+	return region
+}
+
+// duplicateRegion clones every block in region (giving each clone a fresh
+// ID via f.NewBlock) and redirects:
+//   - external predecessors of the original entry t to point at the clone
+//     of t instead,
+//   - successor edges inside the cloned region to point at clones,
+//   - successor edges leaving the cloned region (including back to
+//     primary) to point at the original blocks, so the duplicated tail
+//     rejoins the loop body through primary exactly once.
+//
+// Values are cloned shallowly (same Op/Aux/Type, fresh ID) and phi
+// operands are remapped to reference clones where the corresponding
+// predecessor was cloned. This keeps the function in SSA form: merge
+// points that are no longer reached by more than one predecessor have
+// their phis degenerate to a single operand, which a later copy-elim pass
+// cleans up.
+//
+// Returns the clone of t, so callers that redirect additional edges (see
+// MakeReducible) don't need to re-derive it from the clones map.
+func duplicateRegion(f *Func, t *Block, region []*Block) *Block {
+	clones := make(map[ID]*Block, len(region))
+	origOf := make(map[ID]ID, len(region)) // clone's ID -> the original block it clones
+	for _, b := range region {
+		nb := f.NewBlock(b.Kind)
+		nb.Likely = b.Likely
+		nb.Pos = b.Pos
+		clones[b.ID] = nb
+		origOf[nb.ID] = b.ID
+	}
+	valClones := make(map[ID]*Value)
+	origPreds := make(map[ID][]Edge, len(region)) // b.ID -> b.Preds, snapshotted before any rewiring
+	for _, b := range region {
+		nb := clones[b.ID]
+		for _, v := range b.Values {
+			nv := nb.NewValue0(v.Pos, v.Op, v.Type)
+			nv.Aux = v.Aux
+			nv.AuxInt = v.AuxInt
+			valClones[v.ID] = nv
+		}
+		origPreds[b.ID] = append([]Edge(nil), b.Preds...)
+	}
+	// Wire every clone's successor edges, which is what actually builds
+	// each clone's predecessor list (nb.Preds), before touching any
+	// arg. A phi's operands have to be matched up against nb.Preds
+	// below, and that order isn't settled until every edge -- including
+	// the external redirect onto clonedT further down -- has been wired.
+	for _, b := range region {
+		nb := clones[b.ID]
+		for _, e := range b.Succs {
+			if cs, ok := clones[e.b.ID]; ok {
+				nb.AddEdgeTo(cs)
+			} else {
+				// Leaves the duplicated region: rejoin the
+				// original CFG (typically back at primary).
+				nb.AddEdgeTo(e.b)
+			}
+		}
+	}
+	// Redirect t's external (non-SCC-tail) predecessors to the clone of
+	// t; predecessors that are themselves part of the duplicated region
+	// were already wired to the clone above.
+	clonedT := clones[t.ID]
+	for _, e := range append([]Edge(nil), t.Preds...) {
+		if _, ok := clones[e.b.ID]; ok {
+			continue
+		}
+		e.b.removeSucc(int(e.i))
+		e.b.AddEdgeTo(clonedT)
+	}
+
+	// Now that every clone's predecessor list is final, wire args. An
+	// ordinary value's args aren't tied to predecessor order, so they're
+	// just copied over (remapped through valClones where the arg was
+	// itself duplicated). A phi's args are tied to its block's original
+	// Preds order, and a clone's actual Preds order need not match it --
+	// nb.Preds was built from clone-side successor wiring above, an
+	// entirely separate traversal, and b's own Preds may since have lost
+	// entries too (t's external predecessors were just redirected away
+	// from it) -- so each operand is placed by looking up, for every
+	// actual predecessor of nb, which of b's original predecessors
+	// (origPreds, snapshotted before any of the rewiring above) it is a
+	// clone of (or, for an external predecessor redirected above, simply
+	// itself) and taking v's operand from that original predecessor's
+	// position.
+	for _, b := range region {
+		nb := clones[b.ID]
+		for i, v := range b.Values {
+			nv := nb.Values[i]
+			if v.Op != OpPhi {
+				for _, a := range v.Args {
+					if ca, ok := valClones[a.ID]; ok {
+						nv.AddArg(ca)
+					} else {
+						nv.AddArg(a)
+					}
+				}
+				continue
+			}
+			preds := origPreds[b.ID]
+			for _, pe := range nb.Preds {
+				origPredID := pe.b.ID
+				if id, ok := origOf[pe.b.ID]; ok {
+					origPredID = id
+				}
+				a := v.Args[predIndex(f, preds, origPredID)]
+				if ca, ok := valClones[a.ID]; ok {
+					nv.AddArg(ca)
+				} else {
+					nv.AddArg(a)
+				}
+			}
+		}
+	}
+	return clonedT
+}
+
+// predIndex returns the position of the predecessor with the given block
+// ID within preds (a snapshot of some block b's original Preds, taken
+// before duplicateRegion's rewiring), so a clone's phi operand (built
+// from nb.Preds, the clone's own, independently-ordered and possibly
+// different-length predecessor list) can be matched back to the operand
+// b's original phi held for that same predecessor.
+func predIndex(f *Func, preds []Edge, predID ID) int {
+	for i, e := range preds {
+		if e.b.ID == predID {
+			return i
+		}
+	}
+	// Every predecessor of a clone traces back to one of b's own
+	// original predecessors by construction (see duplicateRegion);
+	// reaching this means the duplicated region's edges don't actually
+	// correspond to the original's, which is a bug in the caller, not a
+	// condition to recover from here.
+	f.Fatalf("predIndex: predecessor %d not found among the original block's predecessors", predID)
+	return -1
+}
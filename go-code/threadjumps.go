@@ -0,0 +1,99 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// ThreadJumps performs bounded jump threading: whenever a BlockPlain
+// block b jumps straight to a BlockIf block t whose Control is a known
+// OpConstBool, b is redirected directly to the branch t would always
+// take, skipping t entirely.
+//
+// Unlike a plain worklist version, every candidate rewrite is checked
+// against the SCC-derived LoopNest (see sccloopnest.go) before being
+// committed: threading is refused when it would (a) redirect an edge
+// from outside a loop to somewhere other than that loop's header,
+// effectively giving the loop a second entry and turning it irreducible,
+// (b) thread into a loop that is already irreducible, or (c) jump
+// between two distinct loops' bodies, merging their headers. Because
+// LoopOf is a cheap map lookup against the cached LoopNest, this check
+// costs O(1) per candidate rather than recomputing global SCCs; the
+// LoopNest itself is only rebuilt (via invalidateCFG) after an edge is
+// actually rewritten.
+//
+// Returns the number of edges rewritten.
+func ThreadJumps(f *Func) int {
+	count := 0
+	for {
+		ln := f.Loops()
+		rewrote := false
+		for _, b := range f.Blocks {
+			t, taken, ok := threadCandidate(b)
+			if !ok {
+				continue
+			}
+			if !threadIsSafe(ln, b, t, taken) {
+				continue
+			}
+			e := b.Succs[0]
+			b.removeSucc(int(e.i))
+			b.AddEdgeTo(taken)
+			f.invalidateCFG()
+			count++
+			rewrote = true
+			break // b.Succs mutated; restart the scan over f.Blocks
+		}
+		if !rewrote {
+			break
+		}
+	}
+	return count
+}
+
+// threadCandidate reports whether b is a BlockPlain jumping straight to a
+// BlockIf block t with a known-constant condition, and if so which of
+// t's successors is always taken.
+func threadCandidate(b *Block) (t, taken *Block, ok bool) {
+	if b.Kind != BlockPlain || len(b.Succs) != 1 {
+		return nil, nil, false
+	}
+	t = b.Succs[0].b
+	if t.Kind != BlockIf || len(t.Succs) != 2 || t.Control == nil || t.Control.Op != OpConstBool {
+		return nil, nil, false
+	}
+	if t.Control.AuxInt != 0 {
+		taken = t.Succs[0].b
+	} else {
+		taken = t.Succs[1].b
+	}
+	if taken == t {
+		return nil, nil, false
+	}
+	return t, taken, true
+}
+
+// threadIsSafe reports whether redirecting b straight to taken (skipping
+// t) preserves the reducibility and loop-nesting invariants recorded in
+// ln.
+func threadIsSafe(ln *LoopNest, b, t, taken *Block) bool {
+	lt := ln.LoopOf(t)
+	if lt == nil {
+		// t isn't part of any loop; there's no loop structure to break.
+		return true
+	}
+	if lt.Irreducible {
+		// Don't grow an already-irreducible loop's entry set further.
+		return false
+	}
+	if lk := ln.LoopOf(taken); lk != lt && lk != nil {
+		// taken belongs to a different loop than t: this would merge
+		// two distinct loops' headers.
+		return false
+	}
+	if lb := ln.LoopOf(b); lb != lt {
+		// b is outside t's loop. Redirecting it anywhere but the header
+		// would give the loop a second entry.
+		return taken == lt.Header
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// isTupleProducer reports whether v is a multi-result call whose actual
+// results are meant to be consumed through OpSelectN projections rather
+// than through v itself.
+func isTupleProducer(v *Value) bool {
+	switch v.Op {
+	case OpStaticCall, OpClosureCall:
+		return true
+	}
+	return false
+}
+
+// restrictTupleLiveness narrows the live sets computeLive just finished:
+// a tuple-producing call's own Value should never need to be live out of
+// the block that defines it, since its only legitimate uses are the
+// OpSelectN projections taken immediately after it. Whatever computeLive
+// path ran may still have carried the call value itself into live-out
+// (none of them know to treat a tuple producer any differently from an
+// ordinary Value), so this strips any such entry out of s.live here,
+// unconditionally, leaving only the projections actually used to
+// contribute to live-out -- the call value dies where it's defined,
+// exactly as if nothing had used it past its own block.
+//
+// In debug mode, stripping one out is also reported: reaching this case
+// at all means some value used the call's combined result directly
+// instead of going through a projection -- the anti-pattern
+// buildFloatPrecCFG's call sites were retrofit away from, onto a single
+// OpStaticCall-plus-OpSelectN shape -- and that's worth flagging even
+// though the narrowing below papers over the live-range cost of it.
+func (s *regAllocState) restrictTupleLiveness() {
+	f := s.f
+	debug := f.pass.debug > regDebug
+	byID := make(map[ID]*Value, f.NumValues())
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			byID[v.ID] = v
+		}
+	}
+	for _, b := range f.Blocks {
+		kept := s.live[b.ID][:0]
+		for _, li := range s.live[b.ID] {
+			v, ok := byID[li.ID]
+			if ok && isTupleProducer(v) {
+				if debug {
+					f.Warnl(v.Pos, "tuple-producing call %v is live out of %v; callers should use its OpSelectN projections instead of the call value itself", v, b)
+				}
+				continue
+			}
+			kept = append(kept, li)
+		}
+		s.live[b.ID] = kept
+	}
+}
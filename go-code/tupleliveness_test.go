@@ -0,0 +1,44 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+// TestRestrictTupleLivenessNarrowsCallLiveOut builds the anti-pattern
+// restrictTupleLiveness's doc comment describes: a block that uses an
+// OpStaticCall's combined result directly across a block boundary
+// instead of going through an OpSelectN projection taken in the call's
+// own block. It checks that, after computeLive, the call value itself
+// is never live out of the block that defines it -- restrictTupleLiveness
+// must have stripped it out of s.live rather than merely reporting it.
+func TestRestrictTupleLivenessNarrowsCallLiveOut(t *testing.T) {
+	c := testConfig(t)
+	memType := types.TypeMem
+
+	fut := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem0", OpInitMem, memType, 0, nil),
+			Valu("call", OpStaticCall, memType, 0, nil, "mem0"),
+			Goto("use")),
+
+		Bloc("use",
+			Exit("call")))
+
+	f := fut.f
+	call := fut.values["call"]
+
+	s := &regAllocState{}
+	s.init(f)
+	s.computeLive()
+
+	for _, li := range s.live[f.Entry.ID] {
+		if li.ID == call.ID {
+			t.Fatalf("expected restrictTupleLiveness to strip the tuple-producing call out of entry's live-out, but it's still live")
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// WTOItem is one element of a WTO: exactly one of Block or Component is
+// set.
+type WTOItem struct {
+	Block     *Block
+	Component *Component
+}
+
+// Component is a nested strongly connected region of a WTO. Head is the
+// block chosen to receive entry edges (the loop header for a reducible
+// loop, or the highest-ranked entry target for an irreducible one); Body
+// is the recursively computed WTO of the region with Head removed, via
+// sccSubgraph.
+type Component struct {
+	Head *Block
+	Body WTO
+}
+
+// WTO is a Bourdoncle-style Weak Topological Order: a flat, recursively
+// nested decomposition of a function's CFG in which every loop
+// (reducible or not) appears as a Component wrapping its body, and
+// acyclic regions appear as plain blocks. It generalizes the flat SCC
+// list with the nesting structure a dataflow fixpoint iteration needs to
+// converge on irreducible loops, where sccSubgraph's recursive-exclusion
+// trick stands in for a true dominance-based loop tree.
+type WTO []WTOItem
+
+// WTO returns the cached Weak Topological Order of f's reachable CFG,
+// computing it if necessary.
+func (f *Func) WTO() WTO {
+	if f.cachedWTO == nil {
+		w := buildWTO(f, f.sccs())
+		f.cachedWTO = &w
+	}
+	return *f.cachedWTO
+}
+
+// buildWTO turns sccs (already in topological order of the condensation
+// DAG) into a WTO, recursing into each non-trivial SCC with its chosen
+// head removed.
+func buildWTO(f *Func, sccs []SCC) WTO {
+	items := make(WTO, 0, len(sccs))
+	for i := range sccs {
+		scc := &sccs[i]
+		if !scc.IsLoop() {
+			items = append(items, WTOItem{Block: scc.Blocks[0]})
+			continue
+		}
+		head := scc.EntryTargets()[0]
+		body := buildWTO(f, sccSubgraph(f, scc.Blocks, head))
+		items = append(items, WTOItem{Component: &Component{Head: head, Body: body}})
+	}
+	return items
+}
+
+// Heads returns every Component head in w, in WTO order: the natural
+// widening points a fixpoint iteration driven by this WTO should apply
+// extrapolation at.
+func (w WTO) Heads() []*Block {
+	var heads []*Block
+	for _, it := range w {
+		if it.Component != nil {
+			heads = append(heads, it.Component.Head)
+			heads = append(heads, it.Component.Body.Heads()...)
+		}
+	}
+	return heads
+}
+
+// NestingDepth returns how many Components b is nested inside (0 for a
+// top-level block or head), or -1 if b does not appear anywhere in w.
+func (w WTO) NestingDepth(b *Block) int {
+	for _, it := range w {
+		if it.Block == b {
+			return 0
+		}
+		if it.Component != nil {
+			if it.Component.Head == b {
+				return 0
+			}
+			if d := it.Component.Body.NestingDepth(b); d >= 0 {
+				return d + 1
+			}
+		}
+	}
+	return -1
+}
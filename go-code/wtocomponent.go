@@ -0,0 +1,79 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// WTOComponent is a node of a Bourdoncle weak topological order: either a
+// singleton block (head set, children empty) or a recursive component
+// rooted at head with a nested WTO of the rest of its SCC.
+//
+// This is a second, independent construction of the same idea as WTO in
+// wto.go — built directly off sccPartition/headerByDominance rather than
+// computeSCCs/EntryTargets — kept as its own type so callers that already
+// depend on sccPartition's block-slice shape aren't forced onto the
+// EntryEdge-based API.
+type WTOComponent struct {
+	head     *Block
+	children []WTOComponent
+}
+
+// Head returns the component's distinguished head block: the sole block,
+// for a singleton; the chosen loop header, for a recursive component.
+func (c WTOComponent) Head() *Block { return c.head }
+
+// Children returns the nested WTO of the component's body (empty for a
+// singleton).
+func (c WTOComponent) Children() []WTOComponent { return c.children }
+
+// Depth returns how many levels of nesting c's deepest descendant has
+// below it (0 for a singleton or a component with no recursive children).
+func (c WTOComponent) Depth() int {
+	max := 0
+	for _, ch := range c.children {
+		if d := ch.Depth() + 1; d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+// Walk calls visit on c and then, in order, on every descendant.
+func (c WTOComponent) Walk(visit func(WTOComponent)) {
+	visit(c)
+	for _, ch := range c.children {
+		ch.Walk(visit)
+	}
+}
+
+// WTO computes a Bourdoncle weak topological order of f's reachable CFG:
+// sccPartition's top-level SCCs (already in reverse topological order),
+// each non-trivial one expanded into a recursive WTOComponent by picking
+// a head (Header() when the SCC is reducible, headerByDominance
+// otherwise) and recursing into sccSubgraph(f, scc.Blocks, head).
+func (f *Func) WTO() []WTOComponent {
+	return wtoOf(f, f.sccs())
+}
+
+func wtoOf(f *Func, sccs []SCC) []WTOComponent {
+	out := make([]WTOComponent, 0, len(sccs))
+	for i := range sccs {
+		scc := &sccs[i]
+		if !scc.IsLoop() {
+			out = append(out, WTOComponent{head: scc.Blocks[0]})
+			continue
+		}
+		head := scc.Header()
+		if head == nil {
+			head = headerByDominance(f.Sdom(), scc.Blocks)
+		}
+		if head == nil {
+			// No unique dominator either (irreducible): fall back to
+			// the first entry target so a head is always produced.
+			head = scc.EntryTargets()[0]
+		}
+		children := wtoOf(f, sccSubgraph(f, scc.Blocks, head))
+		out = append(out, WTOComponent{head: head, children: children})
+	}
+	return out
+}